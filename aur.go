@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// a -bin package for the AUR, see https://wiki.archlinux.org/title/PKGBUILD
+type AurPackage struct {
+	Maintainer  string
+	Name        string
+	Binary      string
+	Version     string
+	Description string
+	URL         string
+	License     string
+	LicenseFile string
+	Sources     []AurSource
+}
+
+// the download of one architecture
+type AurSource struct {
+	Arch   string
+	File   string
+	URL    string
+	SHA256 string
+}
+
+// the arch linux architectures and the targets their archives are built for
+var aurArchitectures = []struct {
+	arch   string
+	goarch string
+}{
+	{"x86_64", "amd64"},
+	{"aarch64", "arm64"},
+}
+
+const aurPkgbuildTemplate = `{{if .Maintainer}}# Maintainer: {{.Maintainer}}
+
+{{end}}pkgname={{.Name}}
+pkgver={{.Version}}
+pkgrel=1
+pkgdesc="{{.Description}}"
+arch=({{range $i, $s := .Sources}}{{if $i}} {{end}}'{{$s.Arch}}'{{end}})
+url="{{.URL}}"
+license=('{{.License}}')
+provides=('{{.Binary}}')
+conflicts=('{{.Binary}}')
+{{range .Sources}}source_{{.Arch}}=("{{.File}}::{{.URL}}")
+{{end}}{{range .Sources}}sha256sums_{{.Arch}}=('{{.SHA256}}')
+{{end}}
+package() {
+  install -Dm755 "$srcdir/{{.Binary}}" "$pkgdir/usr/bin/{{.Binary}}"
+{{- if .LicenseFile}}
+  install -Dm644 "$srcdir/{{.LicenseFile}}" "$pkgdir/usr/share/licenses/$pkgname/LICENSE"
+{{- end}}
+}
+`
+
+const aurSrcinfoTemplate = `pkgbase = {{.Name}}
+	pkgdesc = {{.Description}}
+	pkgver = {{.Version}}
+	pkgrel = 1
+	url = {{.URL}}
+{{- range .Sources}}
+	arch = {{.Arch}}
+{{- end}}
+	license = {{.License}}
+	provides = {{.Binary}}
+	conflicts = {{.Binary}}
+{{- range .Sources}}
+	source_{{.Arch}} = {{.File}}::{{.URL}}
+	sha256sums_{{.Arch}} = {{.SHA256}}
+{{- end}}
+
+pkgname = {{.Name}}
+`
+
+// makepkg does not allow dashes in pkgver, so 1.0.0-rc1 becomes 1.0.0_rc1
+func aurVersion(version string) string {
+	return strings.ReplaceAll(version, "-", "_")
+}
+
+// escape a value for a double quoted bash string
+func aurShellQuote(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(s)
+}
+
+// pick the linux archives of this version from the checksums
+func findAurSources(info ReleaseInfo, sums map[string]string) []AurSource {
+
+	var sources []AurSource
+	for _, a := range aurArchitectures {
+		archive := archiveName(info.name, info.version, Target{goos: "linux", goarch: a.goarch}) + ".tar.gz"
+		hash, ok := sums[archive]
+		if !ok {
+			continue
+		}
+		sources = append(sources, AurSource{
+			Arch:   a.arch,
+			File:   info.name + "-" + aurVersion(info.version) + "-" + a.arch + ".tar.gz",
+			URL:    info.downloadURL(archive),
+			SHA256: hash,
+		})
+	}
+	return sources
+}
+
+// render the PKGBUILD and .SRCINFO of a package
+func renderAurPackage(p AurPackage) (string, string, error) {
+
+	// the description is free text in a double quoted bash string
+	quoted := p
+	quoted.Description = aurShellQuote(p.Description)
+	pkgbuild, err := renderString("PKGBUILD", aurPkgbuildTemplate, quoted)
+	if err != nil {
+		return "", "", err
+	}
+
+	srcinfo, err := renderString(".SRCINFO", aurSrcinfoTemplate, p)
+	if err != nil {
+		return "", "", err
+	}
+	return pkgbuild, srcinfo, nil
+}
+
+// generate a PKGBUILD and .SRCINFO for the linux archives in dist
+func generateAurPackage() error {
+
+	color.Cyan("Generating AUR package...")
+
+	info, err := getReleaseInfo()
+	if err != nil {
+		return err
+	}
+
+	checksum_file := filepath.Join(distDir, checksumsName(info.name, info.version))
+	color.Cyan("Reading the checksums from " + checksum_file + "...")
+	sums, err := readChecksums(checksum_file)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Could not read the checksum file: " + checksum_file)
+		color.White("💬  Make sure you have built the project using gopher release command.")
+		return err
+	}
+
+	color.Cyan("Looking for the linux archives...")
+	sources := findAurSources(info, sums)
+	if len(sources) == 0 {
+		fmt.Print("💥 ")
+		color.Red("There are no Linux x86_64 or arm64 archives for version " + info.version + " in " + checksum_file)
+		return fmt.Errorf("no linux archives in %s", checksum_file)
+	}
+	for _, s := range sources {
+		color.Blue("🆗 " + s.Arch + " source: " + s.URL)
+	}
+
+	pkg := AurPackage{
+		Maintainer:  getMaintainer(),
+		Name:        info.name + "-bin",
+		Binary:      info.name,
+		Version:     aurVersion(info.version),
+		Description: getProjectDescription(),
+		URL:         info.homepage,
+		License:     info.license,
+		LicenseFile: findLicenseFile(),
+		Sources:     sources,
+	}
+
+	if pkg.Maintainer == "" {
+		color.Yellow("⚠  user.name and user.email are not set in git config, the PKGBUILD will have no maintainer.")
+	}
+
+	if pkg.License == "" {
+		color.Yellow("⚠  No LICENSE file found, the PKGBUILD will say custom.")
+		pkg.License = "custom"
+	}
+
+	if pkg.Description == "" {
+		color.Cyan("Adding generic description, you can edit it later...")
+		pkg.Description = "A new AUR package"
+	}
+
+	pkgbuild, srcinfo, err := renderAurPackage(pkg)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return err
+	}
+
+	dir := filepath.Join(distDir, "aur")
+	color.Cyan("Creating the package files in " + dir + "...")
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error creating " + dir)
+		color.Red(err.Error())
+		return err
+	}
+
+	for _, f := range []struct{ name, content string }{{"PKGBUILD", pkgbuild}, {".SRCINFO", srcinfo}} {
+		file := filepath.Join(dir, f.name)
+		err = os.WriteFile(file, []byte(f.content), 0644)
+		if err != nil {
+			fmt.Print("💥 ")
+			color.Red("Error creating " + file)
+			color.Red(err.Error())
+			return err
+		}
+		color.Blue("🆗 " + file)
+	}
+
+	color.Green("✔  AUR package " + pkg.Name + " " + pkg.Version + " created successfully.")
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestAurVersion(t *testing.T) {
+	testCases := []struct {
+		version  string
+		expected string
+	}{
+		{"1.0.0", "1.0.0"},
+		{"1.0.0-rc1", "1.0.0_rc1"},
+		{"1.0.0-beta-2", "1.0.0_beta_2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			if got := aurVersion(tc.version); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAurShellQuote(t *testing.T) {
+	if got := aurShellQuote("a \"quoted\" $HOME `cmd` \\n"); got != "a \\\"quoted\\\" \\$HOME \\`cmd\\` \\\\n" {
+		t.Errorf("unexpected quoting: %s", got)
+	}
+}
+
+func TestGenerateAurPackage(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	gitconfig := filepath.Join(t.TempDir(), "gitconfig")
+	os.WriteFile(gitconfig, []byte("[user]\n\tname = Jane Doe\n\temail = jane@example.com\n"), 0644)
+	t.Setenv("GIT_CONFIG_GLOBAL", gitconfig)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	os.Mkdir(distDir, 0755)
+	os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+	os.WriteFile("main.go", []byte("package main\nconst version = \"2.0.0\""), 0644)
+	os.WriteFile("LICENSE", []byte("SPDX-License-Identifier: MIT\n"), 0644)
+	os.WriteFile("README.md", []byte("# tool\n\nA \"quoted\" tool.\n"), 0644)
+
+	t.Run("missing-checksums", func(t *testing.T) {
+		if err := generateAurPackage(); err == nil {
+			t.Error("expected an error without a checksums file, got nil")
+		}
+	})
+
+	t.Run("no-archives", func(t *testing.T) {
+		os.WriteFile(filepath.Join(distDir, "tool_2.0.0_checksums.txt"), []byte("111  tool_2.0.0_Windows_x86_64.zip\n"), 0644)
+		if err := generateAurPackage(); err == nil {
+			t.Error("expected an error without linux archives, got nil")
+		}
+	})
+
+	t.Run("package", func(t *testing.T) {
+		checksums := "111  tool_2.0.0_Windows_x86_64.zip\n" +
+			"222  tool_2.0.0_Linux_x86_64.tar.gz\n" +
+			"333  tool_2.0.0_Linux_arm64.tar.gz\n" +
+			"444  tool_2.0.0_Linux_i386.tar.gz\n"
+		os.WriteFile(filepath.Join(distDir, "tool_2.0.0_checksums.txt"), []byte(checksums), 0644)
+
+		if err := generateAurPackage(); err != nil {
+			t.Fatalf("generateAurPackage() failed: %v", err)
+		}
+
+		expected := map[string]string{
+			"PKGBUILD": `# Maintainer: Jane Doe <jane@example.com>
+
+pkgname=tool-bin
+pkgver=2.0.0
+pkgrel=1
+pkgdesc="A \"quoted\" tool."
+arch=('x86_64' 'aarch64')
+url="https://github.com/user/tool"
+license=('MIT')
+provides=('tool')
+conflicts=('tool')
+source_x86_64=("tool-2.0.0-x86_64.tar.gz::https://github.com/user/tool/releases/download/v2.0.0/tool_2.0.0_Linux_x86_64.tar.gz")
+source_aarch64=("tool-2.0.0-aarch64.tar.gz::https://github.com/user/tool/releases/download/v2.0.0/tool_2.0.0_Linux_arm64.tar.gz")
+sha256sums_x86_64=('222')
+sha256sums_aarch64=('333')
+
+package() {
+  install -Dm755 "$srcdir/tool" "$pkgdir/usr/bin/tool"
+  install -Dm644 "$srcdir/LICENSE" "$pkgdir/usr/share/licenses/$pkgname/LICENSE"
+}
+`,
+			".SRCINFO": `pkgbase = tool-bin
+	pkgdesc = A "quoted" tool.
+	pkgver = 2.0.0
+	pkgrel = 1
+	url = https://github.com/user/tool
+	arch = x86_64
+	arch = aarch64
+	license = MIT
+	provides = tool
+	conflicts = tool
+	source_x86_64 = tool-2.0.0-x86_64.tar.gz::https://github.com/user/tool/releases/download/v2.0.0/tool_2.0.0_Linux_x86_64.tar.gz
+	sha256sums_x86_64 = 222
+	source_aarch64 = tool-2.0.0-aarch64.tar.gz::https://github.com/user/tool/releases/download/v2.0.0/tool_2.0.0_Linux_arm64.tar.gz
+	sha256sums_aarch64 = 333
+
+pkgname = tool-bin
+`,
+		}
+
+		for name, want := range expected {
+			content, err := os.ReadFile(filepath.Join(distDir, "aur", name))
+			if err != nil {
+				t.Errorf("expected %s to be written: %v", name, err)
+				continue
+			}
+			if string(content) != want {
+				t.Errorf("expected %s:\n%s\ngot:\n%s", name, want, string(content))
+			}
+		}
+	})
+}
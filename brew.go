@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/fatih/color"
+)
+
+// a Homebrew formula, see https://docs.brew.sh/Formula-Cookbook
+type BrewFormula struct {
+	Class       string
+	Description string
+	Homepage    string
+	Version     string
+	License     string
+	Binary      string
+	Platforms   []BrewPlatform
+}
+
+// the on_macos or on_linux block of a formula
+type BrewPlatform struct {
+	Block string
+	Archs []BrewArchive
+}
+
+// the on_intel or on_arm block of a platform
+type BrewArchive struct {
+	Block  string
+	URL    string
+	SHA256 string
+}
+
+// the platforms and cpus homebrew runs on, with the targets their archives are built for
+var brewPlatforms = []struct {
+	block string
+	goos  string
+}{
+	{"on_macos", "darwin"},
+	{"on_linux", "linux"},
+}
+
+var brewArchs = []struct {
+	block  string
+	goarch string
+}{
+	{"on_intel", "amd64"},
+	{"on_arm", "arm64"},
+}
+
+const brewFormulaTemplate = `class {{.Class}} < Formula
+  desc "{{.Description}}"
+  homepage "{{.Homepage}}"
+  version "{{.Version}}"
+{{- if .License}}
+  license "{{.License}}"
+{{- end}}
+{{range .Platforms}}
+  {{.Block}} do
+{{- range .Archs}}
+    {{.Block}} do
+      url "{{.URL}}"
+      sha256 "{{.SHA256}}"
+    end
+{{- end}}
+  end
+{{end}}
+  def install
+    bin.install "{{.Binary}}"
+  end
+
+  test do
+    assert_match version.to_s, shell_output("#{bin}/{{.Binary}} --version")
+  end
+end
+`
+
+// homebrew names the formula class after the file, e.g. my-tool becomes MyTool
+func brewClassName(name string) string {
+
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// pick the darwin and linux archives of this version from the checksums
+func findBrewPlatforms(info ReleaseInfo, sums map[string]string) []BrewPlatform {
+
+	var platforms []BrewPlatform
+	for _, p := range brewPlatforms {
+		platform := BrewPlatform{Block: p.block}
+		for _, a := range brewArchs {
+			archive := archiveName(info.name, info.version, Target{goos: p.goos, goarch: a.goarch}) + ".tar.gz"
+			hash, ok := sums[archive]
+			if !ok {
+				continue
+			}
+			platform.Archs = append(platform.Archs, BrewArchive{Block: a.block, URL: info.downloadURL(archive), SHA256: hash})
+		}
+		if len(platform.Archs) > 0 {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms
+}
+
+// generate a Homebrew formula for the macOS and Linux archives in dist
+func generateBrewFormula() error {
+
+	color.Cyan("Generating Homebrew formula...")
+
+	info, err := getReleaseInfo()
+	if err != nil {
+		return err
+	}
+
+	checksum_file := filepath.Join(distDir, checksumsName(info.name, info.version))
+	color.Cyan("Reading the checksums from " + checksum_file + "...")
+	sums, err := readChecksums(checksum_file)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Could not read the checksum file: " + checksum_file)
+		color.White("💬  Make sure you have built the project using gopher release command.")
+		return err
+	}
+
+	color.Cyan("Looking for the macOS and Linux archives...")
+	platforms := findBrewPlatforms(info, sums)
+	if len(platforms) == 0 {
+		fmt.Print("💥 ")
+		color.Red("There are no Darwin or Linux x86_64 or arm64 archives for version " + info.version + " in " + checksum_file)
+		return fmt.Errorf("no darwin or linux archives in %s", checksum_file)
+	}
+	for _, p := range platforms {
+		for _, a := range p.Archs {
+			color.Blue("🆗 " + p.Block + " " + a.Block + ": " + a.URL)
+		}
+	}
+
+	color.Cyan("Adding generic description, you can edit it later...")
+	formula := BrewFormula{
+		Class:       brewClassName(info.name),
+		Description: "A new Homebrew formula",
+		Homepage:    info.homepage,
+		Version:     info.version,
+		License:     info.license,
+		Binary:      info.name,
+		Platforms:   platforms,
+	}
+
+	color.Cyan("Creating the Homebrew formula...")
+	content, err := renderString("formula", brewFormulaTemplate, formula)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return err
+	}
+
+	formula_path := filepath.Join(distDir, info.name+".rb")
+	color.Cyan("Creating " + formula_path)
+	err = os.WriteFile(formula_path, []byte(content), 0644)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error creating " + formula_path)
+		color.Red(err.Error())
+		return err
+	}
+
+	color.Green("✔  Homebrew formula " + info.name + ".rb created successfully.")
+	return nil
+}
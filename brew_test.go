@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestBrewClassName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"tool", "Tool"},
+		{"my-tool", "MyTool"},
+		{"my_tool.go", "MyToolGo"},
+		{"gopher2", "Gopher2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := brewClassName(tc.name); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestGenerateBrewFormula(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	os.Mkdir(distDir, 0755)
+	os.WriteFile("go.mod", []byte("module github.com/user/my-tool"), 0644)
+	os.WriteFile("main.go", []byte("package main\nconst version = \"2.0.0\""), 0644)
+	os.WriteFile("LICENSE", []byte("SPDX-License-Identifier: MIT\n"), 0644)
+
+	t.Run("missing-checksums", func(t *testing.T) {
+		if err := generateBrewFormula(); err == nil {
+			t.Error("expected an error without a checksums file, got nil")
+		}
+	})
+
+	t.Run("no-archives", func(t *testing.T) {
+		os.WriteFile(filepath.Join(distDir, "my-tool_2.0.0_checksums.txt"), []byte("111  my-tool_2.0.0_Windows_x86_64.zip\n"), 0644)
+		if err := generateBrewFormula(); err == nil {
+			t.Error("expected an error without darwin or linux archives, got nil")
+		}
+	})
+
+	t.Run("formula", func(t *testing.T) {
+		checksums := "111  my-tool_2.0.0_Windows_x86_64.zip\n" +
+			"222  my-tool_2.0.0_Darwin_x86_64.tar.gz\n" +
+			"333  my-tool_2.0.0_Darwin_arm64.tar.gz\n" +
+			"444  my-tool_2.0.0_Linux_x86_64.tar.gz\n" +
+			"555  my-tool_2.0.0_Linux_i386.tar.gz\n"
+		os.WriteFile(filepath.Join(distDir, "my-tool_2.0.0_checksums.txt"), []byte(checksums), 0644)
+
+		if err := generateBrewFormula(); err != nil {
+			t.Fatalf("generateBrewFormula() failed: %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(distDir, "my-tool.rb"))
+		if err != nil {
+			t.Fatalf("expected the formula to be written: %v", err)
+		}
+
+		expected := `class MyTool < Formula
+  desc "A new Homebrew formula"
+  homepage "https://github.com/user/my-tool"
+  version "2.0.0"
+  license "MIT"
+
+  on_macos do
+    on_intel do
+      url "https://github.com/user/my-tool/releases/download/v2.0.0/my-tool_2.0.0_Darwin_x86_64.tar.gz"
+      sha256 "222"
+    end
+    on_arm do
+      url "https://github.com/user/my-tool/releases/download/v2.0.0/my-tool_2.0.0_Darwin_arm64.tar.gz"
+      sha256 "333"
+    end
+  end
+
+  on_linux do
+    on_intel do
+      url "https://github.com/user/my-tool/releases/download/v2.0.0/my-tool_2.0.0_Linux_x86_64.tar.gz"
+      sha256 "444"
+    end
+  end
+
+  def install
+    bin.install "my-tool"
+  end
+
+  test do
+    assert_match version.to_s, shell_output("#{bin}/my-tool --version")
+  end
+end
+`
+		if string(content) != expected {
+			t.Errorf("expected formula:\n%s\ngot:\n%s", expected, string(content))
+		}
+	})
+}
@@ -0,0 +1,582 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// a platform the project is built for
+type Target struct {
+	goos   string
+	goarch string
+	goarm  string
+}
+
+// the platforms goreleaser builds for when .goreleaser.yaml doesn't say
+var (
+	defaultGoos   = []string{"darwin", "linux", "windows"}
+	defaultGoarch = []string{"386", "amd64", "arm64"}
+	defaultGoarm  = "6"
+)
+
+// platforms go can't build for, goreleaser skips them too
+var unsupportedTargets = map[string]bool{
+	"darwin/386": true,
+	"darwin/arm": true,
+}
+
+// the files goreleaser adds to every archive next to the binary
+var archiveExtraFiles = []string{"LICENSE*", "README*", "CHANGELOG*", "license*", "readme*", "changelog*"}
+
+// the result of building one target
+type BuildResult struct {
+	target Target
+	binary string
+	output string
+	err    error
+}
+
+func (t Target) String() string {
+	if t.goarch == "arm" {
+		return t.goos + "/arm/" + t.goarm
+	}
+	return t.goos + "/" + t.goarch
+}
+
+// the os part of an archive name, goreleaser uses title .Os
+func (t Target) osName() string {
+	return strings.ToUpper(t.goos[:1]) + t.goos[1:]
+}
+
+// the arch part of an archive name, following the template made by goreleaser init
+func (t Target) archName() string {
+	switch t.goarch {
+	case "amd64":
+		return "x86_64"
+	case "386":
+		return "i386"
+	case "arm":
+		return "armv" + t.goarm
+	}
+	return t.goarch
+}
+
+// name of the release archive of a target, without the extension
+func archiveName(name string, version string, t Target) string {
+	return name + "_" + version + "_" + t.osName() + "_" + t.archName()
+}
+
+// windows gets zip files, everyone else gets tarballs
+func archiveFormat(t Target) string {
+	if t.goos == "windows" {
+		return "zip"
+	}
+	return "tar.gz"
+}
+
+// name of the checksums file goreleaser writes and scoop reads
+func checksumsName(name string, version string) string {
+	return name + "_" + version + "_checksums.txt"
+}
+
+// build the full list of targets from goos and goarch lists
+func expandTargets(goos []string, goarch []string, goarm []string, ignore []Target) []Target {
+
+	if len(goos) == 0 {
+		goos = defaultGoos
+	}
+	if len(goarch) == 0 {
+		goarch = defaultGoarch
+	}
+	if len(goarm) == 0 {
+		goarm = []string{defaultGoarm}
+	}
+
+	var targets []Target
+	seen := map[Target]bool{}
+	for _, o := range goos {
+		for _, arch := range goarch {
+			arms := []string{""}
+			if arch == "arm" {
+				arms = goarm
+			}
+			for _, arm := range arms {
+				t := Target{goos: o, goarch: arch, goarm: arm}
+				if unsupportedTargets[o+"/"+arch] || isIgnored(t, ignore) || seen[t] {
+					continue
+				}
+				seen[t] = true
+				targets = append(targets, t)
+			}
+		}
+	}
+	return targets
+}
+
+// check the target against the ignore list, empty fields match anything
+func isIgnored(t Target, ignore []Target) bool {
+	for _, i := range ignore {
+		if (i.goos == "" || i.goos == t.goos) && (i.goarch == "" || i.goarch == t.goarch) && (i.goarm == "" || i.goarm == t.goarm) {
+			return true
+		}
+	}
+	return false
+}
+
+// parse a comma separated list of os/arch pairs, e.g. linux/amd64,linux/arm/7
+func parseTargets(value string) ([]Target, error) {
+
+	var targets []Target
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, "/")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid build target %q, use os/arch like linux/amd64", item)
+		}
+		t := Target{goos: parts[0], goarch: parts[1]}
+		if t.goarch == "arm" {
+			t.goarm = defaultGoarm
+			if len(parts) == 3 {
+				t.goarm = parts[2]
+			}
+		} else if len(parts) == 3 {
+			return nil, fmt.Errorf("invalid build target %q, only arm takes a version", item)
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// parse an inline yaml list like [linux, windows] or a single value
+func parseYamlList(value string) []string {
+	value = strings.Trim(strings.TrimSpace(value), "[]")
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.Trim(strings.TrimSpace(item), `"'`)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// read the goos, goarch, goarm and ignore lists from the builds section of a goreleaser config
+// this only understands the plain yaml goreleaser init writes, not anchors or templates
+func parseGoreleaserTargets(content string) ([]Target, bool) {
+
+	lists := map[string][]string{}
+	var ignore []Target
+
+	inBuilds := false
+	key, keyIndent := "", 0
+	ignoreIndent := -1
+
+	for _, line := range strings.Split(content, "\n") {
+
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		text := strings.TrimSpace(line)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		// only the top level builds section matters
+		if indent == 0 {
+			inBuilds = strings.HasPrefix(text, "builds:")
+			key = ""
+			continue
+		}
+		if !inBuilds {
+			continue
+		}
+
+		item := strings.HasPrefix(text, "- ")
+		if item {
+			text = strings.TrimSpace(text[2:])
+			indent += 2
+		}
+
+		// a value of the list started by the previous key
+		if item && key != "" && indent >= keyIndent && !strings.Contains(text, ":") {
+			if ignoreIndent >= 0 {
+				continue
+			}
+			lists[key] = append(lists[key], strings.Trim(text, `"'`))
+			continue
+		}
+
+		name, value, found := strings.Cut(text, ":")
+		if !found {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+
+		// leaving the ignore list
+		if ignoreIndent >= 0 && indent <= ignoreIndent {
+			ignoreIndent = -1
+		}
+
+		if ignoreIndent >= 0 {
+			// every - starts a new ignore entry
+			if item || len(ignore) == 0 {
+				ignore = append(ignore, Target{})
+			}
+			last := &ignore[len(ignore)-1]
+			switch name {
+			case "goos":
+				last.goos = strings.Trim(value, `"'`)
+			case "goarch":
+				last.goarch = strings.Trim(value, `"'`)
+			case "goarm":
+				last.goarm = strings.Trim(value, `"'`)
+			}
+			continue
+		}
+
+		key = ""
+		switch name {
+		case "goos", "goarch", "goarm":
+			if value != "" {
+				lists[name] = append(lists[name], parseYamlList(value)...)
+			} else {
+				key, keyIndent = name, indent
+			}
+		case "ignore":
+			ignoreIndent = indent
+		}
+	}
+
+	if len(lists["goos"]) == 0 && len(lists["goarch"]) == 0 {
+		return nil, false
+	}
+	return expandTargets(lists["goos"], lists["goarch"], lists["goarm"], ignore), true
+}
+
+// get the targets from the build.targets setting, the goreleaser config or the goreleaser defaults
+// returns the targets and where they came from
+func getBuildTargets() ([]Target, string, error) {
+
+	if value := getSettingValue("build.targets", ""); value != "" {
+		targets, err := parseTargets(value)
+		return targets, "the build.targets setting", err
+	}
+
+	for _, file := range []string{".goreleaser.yaml", ".goreleaser.yml"} {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if targets, ok := parseGoreleaserTargets(string(content)); ok {
+			return targets, file, nil
+		}
+	}
+
+	return expandTargets(nil, nil, nil, nil), "the goreleaser defaults", nil
+}
+
+// build the binary of a single target into its own folder in dist
+func buildTarget(name string, version string, t Target) BuildResult {
+
+	binary := name
+	if t.goos == "windows" {
+		binary += ".exe"
+	}
+	folder := name + "_" + t.goos + "_" + t.goarch
+	if t.goarm != "" {
+		folder += "_" + t.goarm
+	}
+	binary = filepath.Join(distDir, folder, binary)
+
+	// the version flag only lands when the version is a variable, like with goreleaser
+	cmd := exec.Command("go", "build", "-ldflags", "-s -w -X main.version="+version, "-o", binary, ".")
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=0", "GOOS="+t.goos, "GOARCH="+t.goarch)
+	if t.goarm != "" {
+		cmd.Env = append(cmd.Env, "GOARM="+t.goarm)
+	}
+	output, err := cmd.CombinedOutput()
+
+	return BuildResult{target: t, binary: binary, output: string(output), err: err}
+}
+
+// build all the targets, running as many go builds at once as there are cpus
+func buildTargets(name string, version string, targets []Target) []BuildResult {
+
+	results := make([]BuildResult, len(targets))
+	limit := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t Target) {
+			defer wg.Done()
+			limit <- struct{}{}
+			results[i] = buildTarget(name, version, t)
+			<-limit
+		}(i, t)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// the readme, license and changelog files in the project root
+func getArchiveExtraFiles() []string {
+
+	seen := map[string]bool{}
+	var files []string
+	for _, pattern := range archiveExtraFiles {
+		matches, _ := filepath.Glob(pattern)
+		for _, m := range matches {
+			if info, err := os.Stat(m); err != nil || info.IsDir() || seen[m] {
+				continue
+			}
+			seen[m] = true
+			files = append(files, m)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// write a zip archive with the given files at its root
+func writeZip(path string, files []string) error {
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Method = zip.Deflate
+
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if err := copyFileTo(w, file); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// write a gzipped tarball with the given files at its root
+func writeTarGz(path string, files []string) error {
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if err := copyFileTo(tw, file); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// copy the content of a file to a writer
+func copyFileTo(w io.Writer, file string) error {
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	_, err = io.Copy(w, in)
+	return err
+}
+
+// get the sha256 of a file as a hex string
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// write the checksums file for the archives in the goreleaser format
+func writeChecksums(path string, archives []string) error {
+
+	sorted := append([]string{}, archives...)
+	sort.Strings(sorted)
+
+	var b strings.Builder
+	for _, archive := range sorted {
+		sum, err := fileSHA256(archive)
+		if err != nil {
+			return err
+		}
+		b.WriteString(sum + "  " + filepath.Base(archive) + "\n")
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// cross compile the project, and with release also archive the binaries and checksum them
+func build(opts BuildOptions) error {
+
+	err := checkTools("go")
+	if err != nil {
+		return err
+	}
+
+	name, em := getModuleName()
+	if em != nil {
+		return em
+	}
+
+	mainfile, en := getMainFileName()
+	if en != nil {
+		return en
+	}
+
+	loc, ev := getVersionLocation(mainfile + ".go")
+	if ev != nil {
+		return ev
+	}
+	version := loc.value
+
+	color.Cyan("Reading the build targets...")
+	targets, from, et := getBuildTargets()
+	if et != nil {
+		fmt.Print("💥 ")
+		color.Red(et.Error())
+		return et
+	}
+	if len(targets) == 0 {
+		fmt.Print("💥 ")
+		color.Red("There is nothing to build, " + from + " has no targets.")
+		return fmt.Errorf("no build targets in %s", from)
+	}
+	color.Blue(fmt.Sprintf("🆗 Got %d targets from %s.", len(targets), from))
+
+	// start from an empty dist folder like goreleaser --clean
+	color.Cyan("Cleaning the " + distDir + " folder...")
+	if err := os.RemoveAll(distDir); err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return err
+	}
+
+	color.Cyan(fmt.Sprintf("Building %s %s for %d targets...", name, version, len(targets)))
+	results := buildTargets(name, version, targets)
+
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			color.Red(fmt.Sprintf("  ✘  %-16s %s", r.target, r.err.Error()))
+			fmt.Fprint(os.Stderr, r.output)
+		} else {
+			color.Green(fmt.Sprintf("  ✔  %-16s %s", r.target, r.binary))
+		}
+	}
+	if failed > 0 {
+		fmt.Print("💥 ")
+		color.Red(fmt.Sprintf("%d of %d builds failed.", failed, len(results)))
+		return fmt.Errorf("%d builds failed", failed)
+	}
+	color.Blue("🆗 All the targets were built.")
+
+	if !opts.release {
+		color.Green("✔  Project built successfully.")
+		return nil
+	}
+
+	extra := getArchiveExtraFiles()
+	if len(extra) > 0 {
+		color.Blue("🆗 Adding " + strings.Join(extra, ", ") + " to the archives.")
+	}
+
+	var archives []string
+	for _, r := range results {
+		archive := filepath.Join(distDir, archiveName(name, version, r.target)+"."+archiveFormat(r.target))
+		color.Cyan("Creating " + archive + "...")
+
+		// the archives are flat like the ones goreleaser makes
+		files := append([]string{r.binary}, extra...)
+		if archiveFormat(r.target) == "zip" {
+			err = writeZip(archive, files)
+		} else {
+			err = writeTarGz(archive, files)
+		}
+		if err != nil {
+			fmt.Print("💥 ")
+			color.Red("Error creating " + archive)
+			color.Red(err.Error())
+			return err
+		}
+		archives = append(archives, archive)
+	}
+
+	checksums := filepath.Join(distDir, checksumsName(name, version))
+	color.Cyan("Writing " + checksums + "...")
+	err = writeChecksums(checksums, archives)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error writing " + checksums)
+		color.Red(err.Error())
+		return err
+	}
+
+	err = listDistArtifacts()
+	if err != nil {
+		return err
+	}
+
+	color.Green("✔  Release archives built successfully.")
+	return nil
+}
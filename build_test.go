@@ -0,0 +1,271 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestArchiveName(t *testing.T) {
+	testCases := []struct {
+		target   Target
+		expected string
+		format   string
+	}{
+		{Target{goos: "linux", goarch: "amd64"}, "tool_1.0.0_Linux_x86_64", "tar.gz"},
+		{Target{goos: "windows", goarch: "386"}, "tool_1.0.0_Windows_i386", "zip"},
+		{Target{goos: "windows", goarch: "arm64"}, "tool_1.0.0_Windows_arm64", "zip"},
+		{Target{goos: "darwin", goarch: "arm64"}, "tool_1.0.0_Darwin_arm64", "tar.gz"},
+		{Target{goos: "linux", goarch: "arm", goarm: "7"}, "tool_1.0.0_Linux_armv7", "tar.gz"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.target.String(), func(t *testing.T) {
+			if got := archiveName("tool", "1.0.0", tc.target); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+			if got := archiveFormat(tc.target); got != tc.format {
+				t.Errorf("expected format %q, got %q", tc.format, got)
+			}
+		})
+	}
+}
+
+func TestParseTargets(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected []string
+		wantErr  bool
+	}{
+		{"linux/amd64", []string{"linux/amd64"}, false},
+		{"linux/amd64, windows/arm64,", []string{"linux/amd64", "windows/arm64"}, false},
+		{"linux/arm", []string{"linux/arm/6"}, false},
+		{"linux/arm/7", []string{"linux/arm/7"}, false},
+		{"linux", nil, true},
+		{"linux/amd64/v3", nil, true},
+		{"/amd64", nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			targets, err := parseTargets(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if got := targetStrings(targets); strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseGoreleaserTargets(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected []string
+		ok       bool
+	}{
+		{
+			"goreleaser-init",
+			`version: 2
+
+before:
+  hooks:
+    - go mod tidy
+
+builds:
+  - env:
+      - CGO_ENABLED=0
+    goos:
+      - linux
+      - windows
+      - darwin
+
+archives:
+  - formats: [tar.gz]
+`,
+			[]string{"linux/386", "linux/amd64", "linux/arm64", "windows/386", "windows/amd64", "windows/arm64", "darwin/amd64", "darwin/arm64"},
+			true,
+		},
+		{
+			"inline-lists",
+			"builds:\n  - goos: [linux, \"windows\"]\n    goarch: [amd64, arm]\n    goarm: [7]\n",
+			[]string{"linux/amd64", "linux/arm/7", "windows/amd64", "windows/arm/7"},
+			true,
+		},
+		{
+			"ignore",
+			`builds:
+  - goos:
+      - linux
+      - windows
+    goarch:
+      - amd64
+      - arm64 # comment
+    ignore:
+      - goos: windows
+        goarch: arm64
+    ldflags:
+      - -s -w
+`,
+			[]string{"linux/amd64", "linux/arm64", "windows/amd64"},
+			true,
+		},
+		{
+			"no-builds",
+			"archives:\n  - goos: linux\n",
+			nil,
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			targets, ok := parseGoreleaserTargets(tc.content)
+			if ok != tc.ok {
+				t.Fatalf("expected ok %v, got %v", tc.ok, ok)
+			}
+			if got := targetStrings(targets); strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	// the failing build prints its output to stderr
+	origStdout := os.Stdout
+	origStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	os.Stderr = w
+	defer func() {
+		os.Stdout = origStdout
+		os.Stderr = origStderr
+	}()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	os.WriteFile("go.mod", []byte("module github.com/user/tool\n\ngo 1.21\n"), 0644)
+	os.WriteFile("main.go", []byte("package main\n\nconst version = \"1.2.3\"\n\nfunc main() { println(version) }\n"), 0644)
+	os.WriteFile("README.md", []byte("# tool\n"), 0644)
+	os.WriteFile(projectConfigFile, []byte("[build]\ntargets = [\"linux/amd64\", \"windows/amd64\"]\n"), 0644)
+
+	t.Run("binaries-only", func(t *testing.T) {
+		if err := build(BuildOptions{}); err != nil {
+			t.Fatalf("build() failed: %v\n%s", err, buff.String())
+		}
+		for _, binary := range []string{"tool_linux_amd64/tool", "tool_windows_amd64/tool.exe"} {
+			if _, err := os.Stat(filepath.Join(distDir, binary)); err != nil {
+				t.Errorf("expected %s to be built: %v", binary, err)
+			}
+		}
+		if artifacts, _ := getDistArtifacts(); len(artifacts) != 0 {
+			t.Errorf("expected no archives without --release, got %v", artifacts)
+		}
+	})
+
+	t.Run("release", func(t *testing.T) {
+		if err := build(BuildOptions{release: true}); err != nil {
+			t.Fatalf("build() failed: %v\n%s", err, buff.String())
+		}
+
+		tarball := filepath.Join(distDir, "tool_1.2.3_Linux_x86_64.tar.gz")
+		if got := tarEntries(t, tarball); strings.Join(got, ",") != "tool,README.md" {
+			t.Errorf("expected the tarball to hold the binary and the readme, got %v", got)
+		}
+
+		zipfile := filepath.Join(distDir, "tool_1.2.3_Windows_x86_64.zip")
+		zr, err := zip.OpenReader(zipfile)
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", zipfile, err)
+		}
+		defer zr.Close()
+		var names []string
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		if strings.Join(names, ",") != "tool.exe,README.md" {
+			t.Errorf("expected the zip to hold the binary and the readme, got %v", names)
+		}
+
+		checksums, err := os.ReadFile(filepath.Join(distDir, "tool_1.2.3_checksums.txt"))
+		if err != nil {
+			t.Fatalf("expected a checksums file: %v", err)
+		}
+		sum, _ := fileSHA256(zipfile)
+		if !strings.Contains(string(checksums), sum+"  tool_1.2.3_Windows_x86_64.zip\n") {
+			t.Errorf("expected the checksum of the zip, got %q", string(checksums))
+		}
+	})
+
+	t.Run("scoop-reads-the-archives", func(t *testing.T) {
+		if err := generateScoopFile(ScoopOptions{}); err != nil {
+			t.Fatalf("generateScoopFile() failed: %v", err)
+		}
+		manifest, _ := os.ReadFile(filepath.Join(distDir, "tool.json"))
+		sum, _ := fileSHA256(filepath.Join(distDir, "tool_1.2.3_Windows_x86_64.zip"))
+		if !strings.Contains(string(manifest), sum) {
+			t.Errorf("expected the manifest to have the zip checksum, got %q", string(manifest))
+		}
+	})
+
+	t.Run("bad-target", func(t *testing.T) {
+		os.WriteFile(projectConfigFile, []byte("[build]\ntargets = \"plan10/amd64\"\n"), 0644)
+		if err := build(BuildOptions{}); err == nil {
+			t.Error("expected an error for an unknown platform, got nil")
+		}
+	})
+}
+
+func targetStrings(targets []Target) []string {
+	var names []string
+	for _, t := range targets {
+		names = append(names, t.String())
+	}
+	return names
+}
+
+func tarEntries(t *testing.T, path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	tr := tar.NewReader(gr)
+	var names []string
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, h.Name)
+	}
+	return names
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// name of the changelog file in the project root
+const changelogFile = "CHANGELOG.md"
+
+// the top of a new changelog, as suggested by https://keepachangelog.com
+const changelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// the Keep a Changelog categories in the order they are listed
+var changelogCategories = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// pick the changelog category of a commit, returns false for commits that don't belong in it
+func getChangelogCategory(cc ConventionalCommit) (string, bool) {
+	switch {
+	case cc.kind == "security" || cc.scope == "security":
+		return "Security", true
+	case cc.breaking:
+		return "Changed", true
+	case cc.kind == "feat":
+		return "Added", true
+	case cc.kind == "fix":
+		return "Fixed", true
+	case cc.kind == "perf" || cc.kind == "refactor":
+		return "Changed", true
+	case cc.kind == "deprecate":
+		return "Deprecated", true
+	case cc.kind == "revert" || cc.kind == "remove":
+		return "Removed", true
+	}
+	return "", false
+}
+
+// render a changelog section with the given title from a list of commits
+func renderChangelogSection(title string, commits []Commit) (string, int) {
+
+	entries := map[string][]string{}
+	count := 0
+
+	for _, c := range commits {
+		cc, ok := parseConventionalCommit(c)
+		if !ok {
+			continue
+		}
+		category, ok := getChangelogCategory(cc)
+		if !ok {
+			continue
+		}
+
+		entry := "- "
+		if cc.breaking {
+			entry += "**BREAKING:** "
+		}
+		if cc.scope != "" {
+			entry += "**" + cc.scope + ":** "
+		}
+		entry += cc.description + " (" + c.hash + ")"
+
+		entries[category] = append(entries[category], entry)
+		count++
+	}
+
+	var b strings.Builder
+	b.WriteString("## " + title + "\n")
+	for _, category := range changelogCategories {
+		if len(entries[category]) == 0 {
+			continue
+		}
+		b.WriteString("\n### " + category + "\n\n")
+		// git log lists the newest commit first, the changelog reads better oldest first
+		for i := len(entries[category]) - 1; i >= 0; i-- {
+			b.WriteString(entries[category][i] + "\n")
+		}
+	}
+
+	return b.String(), count
+}
+
+// insert a section above the previous releases of a changelog
+// the header stays on top and the previous releases are kept as is
+// an Unreleased section is emptied, its changes are the ones being released
+func insertChangelogSection(changelog string, section string) string {
+
+	if changelog == "" {
+		return changelogHeader + "\n" + section
+	}
+	if !strings.HasSuffix(changelog, "\n") {
+		changelog += "\n"
+	}
+
+	lines := strings.SplitAfter(changelog, "\n")
+	top := len(lines)
+	unreleased := -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(line), "## [unreleased]") {
+			unreleased = i
+			continue
+		}
+		top = i
+		break
+	}
+
+	// keep the Unreleased heading but drop its entries
+	before := strings.Join(lines[:top], "")
+	if unreleased >= 0 {
+		before = strings.Join(lines[:unreleased+1], "")
+	}
+
+	// no releases yet, add it to the end
+	if top == len(lines) {
+		return before + "\n" + section
+	}
+	if unreleased >= 0 {
+		before += "\n"
+	}
+	return before + section + "\n" + strings.Join(lines[top:], "")
+}
+
+// write the changelog section of the current version, or preview the unreleased one
+func generateChangelog(unreleased bool) error {
+
+	color.Cyan("Getting the latest git tag...")
+	tag := getGitTag()
+	if tag == "unknown" {
+		color.Yellow("⚠  No git tag found, using all the commits.")
+	} else {
+		color.Blue("🆗 Latest tag is " + tag)
+	}
+
+	color.Cyan("Reading the commits since " + tag + "...")
+	commits, err := getCommitsSince(tag)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return err
+	}
+
+	var title, version string
+	if unreleased {
+		title = "[Unreleased]"
+	} else {
+		color.Cyan("Determining the name of the main file...")
+		name, em := getMainFileName()
+		if em != nil {
+			return em
+		}
+
+		color.Cyan("Getting version from " + name + ".go file...")
+		version, err = getVersion(name + ".go")
+		if err != nil {
+			return err
+		}
+		color.Blue("🆗 Got the project version: " + version)
+
+		title = "[" + version + "] - " + time.Now().Format("2006-01-02")
+	}
+
+	section, count := renderChangelogSection(title, commits)
+	color.Blue(fmt.Sprintf("🆗 %d of %d commits go into the changelog.", count, len(commits)))
+
+	if unreleased {
+		fmt.Println()
+		fmt.Print(section)
+		fmt.Println()
+		return nil
+	}
+
+	content, err := os.ReadFile(changelogFile)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Print("💥 ")
+		color.Red("Error reading " + changelogFile)
+		color.Red(err.Error())
+		return err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "## ["+version+"]") {
+			fmt.Print("💥 ")
+			color.Red(changelogFile + " already has a section for " + version + ". Bump the version first.")
+			return fmt.Errorf("%s already has a section for %s", changelogFile, version)
+		}
+	}
+
+	color.Cyan("Writing the " + version + " section to " + changelogFile + "...")
+	err = os.WriteFile(changelogFile, []byte(insertChangelogSection(string(content), section)), 0644)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error writing " + changelogFile)
+		color.Red(err.Error())
+		return err
+	}
+
+	color.Green("✔  " + changelogFile + " updated for version " + version)
+	return nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+func TestRenderChangelogSection(t *testing.T) {
+
+	// newest first, the way git log lists them
+	commits := []Commit{
+		{hash: "f00d005", subject: "docs: update readme"},
+		{hash: "f00d004", subject: "feat(cli)!: rename --out to --output"},
+		{hash: "f00d003", subject: "fix: crash on empty input"},
+		{hash: "f00d002", subject: "feat: add --json flag"},
+		{hash: "f00d001", subject: "feat: add --quiet flag"},
+		{hash: "f00d000", subject: "Initial commit"},
+	}
+
+	section, count := renderChangelogSection("[1.1.0] - 2024-01-02", commits)
+
+	expected := `## [1.1.0] - 2024-01-02
+
+### Added
+
+- add --quiet flag (f00d001)
+- add --json flag (f00d002)
+
+### Changed
+
+- **BREAKING:** **cli:** rename --out to --output (f00d004)
+
+### Fixed
+
+- crash on empty input (f00d003)
+`
+	if section != expected {
+		t.Errorf("expected section:\n%s\ngot:\n%s", expected, section)
+	}
+	if count != 4 {
+		t.Errorf("expected 4 entries, got %d", count)
+	}
+}
+
+func TestInsertChangelogSection(t *testing.T) {
+
+	section := "## [1.1.0] - 2024-01-02\n\n### Fixed\n\n- a bug (abc)\n"
+
+	testCases := []struct {
+		name     string
+		existing string
+		expected string
+	}{
+		{
+			"new-file",
+			"",
+			changelogHeader + "\n" + section,
+		},
+		{
+			"above-previous-releases",
+			"# Changelog\n\nIntro.\n\n## [1.0.0] - 2023-01-01\n\n- first\n",
+			"# Changelog\n\nIntro.\n\n" + section + "\n## [1.0.0] - 2023-01-01\n\n- first\n",
+		},
+		{
+			"empties-unreleased",
+			"# Changelog\n\n## [Unreleased]\n\n### Fixed\n\n- a bug (abc)\n\n## [1.0.0] - 2023-01-01\n",
+			"# Changelog\n\n## [Unreleased]\n\n" + section + "\n## [1.0.0] - 2023-01-01\n",
+		},
+		{
+			"empties-unreleased-without-releases",
+			"# Changelog\n\n## [Unreleased]\n\n### Fixed\n\n- a bug (abc)\n",
+			"# Changelog\n\n## [Unreleased]\n\n" + section,
+		},
+		{
+			"no-sections-yet",
+			"# Changelog",
+			"# Changelog\n\n" + section,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := insertChangelogSection(tc.existing, section); got != tc.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestGenerateChangelog(t *testing.T) {
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	git := setupTestRepo(t)
+
+	os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+	os.WriteFile("main.go", []byte("package main\n\nconst version = \"1.1.0\"\n"), 0644)
+	previous := "# Changelog\n\n## [1.0.0] - 2023-01-01\n\n### Added\n\n- the first release\n"
+	os.WriteFile(changelogFile, []byte(previous), 0644)
+
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "feat: first feature")
+	git("tag", "v1.0.0")
+	git("commit", "-q", "--allow-empty", "-m", "feat: second feature")
+	git("commit", "-q", "--allow-empty", "-m", "fix: a bug")
+
+	t.Run("unreleased-preview", func(t *testing.T) {
+		if err := generateChangelog(true); err != nil {
+			t.Fatalf("generateChangelog() failed: %v", err)
+		}
+		w.Close()
+		var out bytes.Buffer
+		out.ReadFrom(r)
+		if !strings.Contains(out.String(), "## [Unreleased]") || !strings.Contains(out.String(), "- second feature") {
+			t.Errorf("expected the preview to list the unreleased changes, got %q", out.String())
+		}
+
+		content, _ := os.ReadFile(changelogFile)
+		if string(content) != previous {
+			t.Errorf("expected --unreleased to leave %s untouched, got %q", changelogFile, string(content))
+		}
+	})
+
+	_, w, _ = os.Pipe()
+	os.Stdout = w
+
+	t.Run("release-section", func(t *testing.T) {
+		if err := generateChangelog(false); err != nil {
+			t.Fatalf("generateChangelog() failed: %v", err)
+		}
+		content, _ := os.ReadFile(changelogFile)
+		text := string(content)
+
+		heading := "## [1.1.0] - " + time.Now().Format("2006-01-02")
+		if !strings.Contains(text, heading) {
+			t.Errorf("expected %q in the changelog, got %q", heading, text)
+		}
+		if strings.Contains(text, "first feature") {
+			t.Error("expected commits before the last tag to be left out")
+		}
+		if !strings.HasSuffix(text, previous[len("# Changelog\n\n"):]) {
+			t.Errorf("expected the earlier sections to be preserved, got %q", text)
+		}
+	})
+
+	t.Run("section-exists", func(t *testing.T) {
+		if err := generateChangelog(false); err == nil {
+			t.Error("expected an error when the version already has a section, got nil")
+		}
+	})
+
+	t.Run("filled-unreleased", func(t *testing.T) {
+		os.WriteFile("main.go", []byte("package main\n\nconst version = \"1.2.0\"\n"), 0644)
+		unreleased := "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- second feature\n\n" + previous[len("# Changelog\n\n"):]
+		os.WriteFile(changelogFile, []byte(unreleased), 0644)
+
+		if err := generateChangelog(false); err != nil {
+			t.Fatalf("generateChangelog() failed: %v", err)
+		}
+		content, _ := os.ReadFile(changelogFile)
+		text := string(content)
+
+		if n := strings.Count(text, "second feature"); n != 1 {
+			t.Errorf("expected the change to be listed once, got %d times in %q", n, text)
+		}
+		if !strings.HasPrefix(text, "# Changelog\n\n## [Unreleased]\n\n## [1.2.0] - ") {
+			t.Errorf("expected an empty Unreleased section above the release, got %q", text)
+		}
+	})
+}
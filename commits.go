@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// a single commit from the git log
+type Commit struct {
+	hash    string
+	subject string
+	body    string
+}
+
+// a commit message following https://www.conventionalcommits.org
+type ConventionalCommit struct {
+	kind        string
+	scope       string
+	breaking    bool
+	description string
+}
+
+// matches the type(scope)!: description header of a conventional commit
+var conventionalHeader = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^)]*)\))?(!)?: (.+)$`)
+
+// separators used to split the git log output, they can't appear in commit messages
+const (
+	logFieldSep  = "\x1f"
+	logRecordSep = "\x1e"
+)
+
+// get the commits reachable from HEAD but not from the given tag
+// when there is no tag all the commits are returned
+func getCommitsSince(tag string) ([]Commit, error) {
+
+	args := []string{"log", "--no-merges", "--format=%h" + logFieldSep + "%s" + logFieldSep + "%b" + logRecordSep}
+	if tag != "" && tag != "unknown" {
+		args = append(args, tag+"..HEAD")
+	}
+
+	cmd := exec.Command("git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error reading the git log: %w", err)
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(output), logRecordSep) {
+		fields := strings.Split(strings.TrimSpace(record), logFieldSep)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, Commit{hash: fields[0], subject: fields[1], body: strings.TrimSpace(fields[2])})
+	}
+	return commits, nil
+}
+
+// parse the message of a commit, returns false if it isn't a conventional commit
+func parseConventionalCommit(c Commit) (ConventionalCommit, bool) {
+
+	m := conventionalHeader.FindStringSubmatch(strings.TrimSpace(c.subject))
+	if m == nil {
+		return ConventionalCommit{}, false
+	}
+
+	cc := ConventionalCommit{
+		kind:        strings.ToLower(m[1]),
+		scope:       m[2],
+		breaking:    m[3] == "!",
+		description: m[4],
+	}
+
+	for _, line := range strings.Split(c.body, "\n") {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			cc.breaking = true
+		}
+	}
+
+	return cc, true
+}
+
+// pick the bump level from a list of commits
+// returns an empty level when no commit calls for a release
+func getBumpLevel(commits []Commit) (string, []Commit) {
+
+	var major, minor, patch []Commit
+
+	for _, c := range commits {
+		cc, ok := parseConventionalCommit(c)
+		if !ok {
+			continue
+		}
+		switch {
+		case cc.breaking:
+			major = append(major, c)
+		case cc.kind == "feat":
+			minor = append(minor, c)
+		case cc.kind == "fix":
+			patch = append(patch, c)
+		}
+	}
+
+	switch {
+	case len(major) > 0:
+		return "major", major
+	case len(minor) > 0:
+		return "minor", minor
+	case len(patch) > 0:
+		return "patch", patch
+	}
+	return "", nil
+}
+
+// read the commits since the last tag and decide how to bump the version
+func getAutoBumpLevel() (string, error) {
+
+	color.Cyan("Getting the latest git tag...")
+	tag := getGitTag()
+	if tag == "unknown" {
+		color.Yellow("⚠  No git tag found, looking at all the commits.")
+	} else {
+		color.Blue("🆗 Latest tag is " + tag)
+	}
+
+	color.Cyan("Reading the commits since " + tag + "...")
+	commits, err := getCommitsSince(tag)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return "", err
+	}
+
+	level, drivers := getBumpLevel(commits)
+	if level == "" {
+		fmt.Print("💥 ")
+		color.Red(fmt.Sprintf("None of the %d commits since %s is a feat, fix or breaking change, nothing to release.", len(commits), tag))
+		return "", fmt.Errorf("no commits to release since %s", tag)
+	}
+
+	color.Blue(fmt.Sprintf("🆗 %d of %d commits call for a %s bump:", len(drivers), len(commits), level))
+	for _, c := range drivers {
+		color.White("  " + c.hash + " " + c.subject)
+	}
+
+	return level, nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	testCases := []struct {
+		subject  string
+		body     string
+		ok       bool
+		kind     string
+		scope    string
+		breaking bool
+	}{
+		{"feat: add a flag", "", true, "feat", "", false},
+		{"fix(parser): handle tabs", "", true, "fix", "parser", false},
+		{"feat(api)!: drop v1", "", true, "feat", "api", true},
+		{"chore!: drop go 1.20", "", true, "chore", "", true},
+		{"refactor: split files", "some text\n\nBREAKING CHANGE: renamed flags", true, "refactor", "", true},
+		{"Fix: capitalized type", "", true, "fix", "", false},
+		{"Update README", "", false, "", "", false},
+		{"feat add a flag", "", false, "", "", false},
+		{"feat:", "", false, "", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.subject, func(t *testing.T) {
+			cc, ok := parseConventionalCommit(Commit{subject: tc.subject, body: tc.body})
+			if ok != tc.ok {
+				t.Fatalf("expected ok %v, got %v", tc.ok, ok)
+			}
+			if cc.kind != tc.kind || cc.scope != tc.scope || cc.breaking != tc.breaking {
+				t.Errorf("expected (%q, %q, %v), got (%q, %q, %v)", tc.kind, tc.scope, tc.breaking, cc.kind, cc.scope, cc.breaking)
+			}
+		})
+	}
+}
+
+func TestGetBumpLevel(t *testing.T) {
+	testCases := []struct {
+		name     string
+		subjects []string
+		level    string
+		drivers  int
+	}{
+		{"patch", []string{"fix: one", "docs: readme", "fix: two"}, "patch", 2},
+		{"minor", []string{"fix: one", "feat: new", "chore: deps"}, "minor", 1},
+		{"major", []string{"feat: new", "fix!: changed output", "feat: other"}, "major", 1},
+		{"nothing", []string{"docs: readme", "Merge branch main"}, "", 0},
+		{"empty", nil, "", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var commits []Commit
+			for _, s := range tc.subjects {
+				commits = append(commits, Commit{hash: "abc", subject: s})
+			}
+			level, drivers := getBumpLevel(commits)
+			if level != tc.level {
+				t.Errorf("expected level %q, got %q", tc.level, level)
+			}
+			if len(drivers) != tc.drivers {
+				t.Errorf("expected %d driving commits, got %d", tc.drivers, len(drivers))
+			}
+		})
+	}
+}
+
+func TestVersionBumpAuto(t *testing.T) {
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	git := setupTestRepo(t)
+
+	os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+	os.WriteFile("main.go", []byte("package main\n\nconst version = \"1.2.3\"\n"), 0644)
+
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "feat: first feature")
+	git("tag", "v1.2.3")
+	git("commit", "-q", "--allow-empty", "-m", "docs: update readme")
+
+	t.Run("nothing-to-release", func(t *testing.T) {
+		if err := versionBump("auto", BumpOptions{}); err == nil {
+			t.Error("expected an error when no commit calls for a release, got nil")
+		}
+	})
+
+	t.Run("fix", func(t *testing.T) {
+		git("commit", "-q", "--allow-empty", "-m", "fix: crash on empty input")
+		if err := versionBump("auto", BumpOptions{}); err != nil {
+			t.Fatalf("versionBump() failed: %v", err)
+		}
+		if version, _ := getVersion("main.go"); version != "1.2.4" {
+			t.Errorf("expected version %q, got %q", "1.2.4", version)
+		}
+	})
+
+	t.Run("breaking-change", func(t *testing.T) {
+		os.WriteFile("main.go", []byte("package main\n\nconst version = \"1.2.3\"\n"), 0644)
+		git("commit", "-q", "--allow-empty", "-m", "refactor: new config format", "-m", "BREAKING CHANGE: old files are not read")
+		if err := versionBump("auto", BumpOptions{}); err != nil {
+			t.Fatalf("versionBump() failed: %v", err)
+		}
+		if version, _ := getVersion("main.go"); version != "2.0.0" {
+			t.Errorf("expected version %q, got %q", "2.0.0", version)
+		}
+	})
+}
+
+// isolate git from the user's config and return a helper running git in the current directory
+func setupTestRepo(t *testing.T) func(args ...string) {
+
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	return func(args ...string) {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+}
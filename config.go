@@ -0,0 +1,334 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/fatih/color"
+)
+
+// name of the per-project config file, looked up in the current directory
+const projectConfigFile = ".gopher.toml"
+
+// a configuration value gopher understands
+type Setting struct {
+	key  string
+	env  string
+	def  string
+	help string
+}
+
+// all the known settings, in the order they are listed
+var settings = []Setting{
+	{key: "username", env: "GOPHER_USERNAME", help: "your username on the git host"},
+	{key: "forge", env: "GOPHER_FORGE", help: "forge running on a self-hosted git host: github, gitlab or gitea"},
+	{key: "install_path", env: "GOPHER_INSTALLPATH", help: "directory gopher install copies binaries to"},
+	{key: "init.template", def: defaultTemplate, help: "project template used by init"},
+	{key: "init.host", def: "github.com", help: "git host used by init"},
+	{key: "init.origin_style", def: "ssh", help: "git origin format used by init: ssh or https"},
+	{key: "init.branch", def: "main", help: "initial git branch used by init"},
+	{key: "init.license", help: "license added by init"},
+	{key: "version.source", def: "go", help: "where the version is kept: go, file or git"},
+	{key: "version.file", def: "VERSION", help: "file holding the version when version.source is file"},
+	{key: "bump.commit_message", def: "chore: release v{{.Version}}", help: "commit message template used by bump --commit"},
+	{key: "bump.tag_message", def: "Release v{{.Version}}", help: "tag message template used by bump --tag"},
+	{key: "release.checks", def: "clean,branch,tag,version,tests", help: "pre-flight checks run before release tags"},
+	{key: "release.branch", def: "main", help: "branches releases are made from, comma separated"},
+	{key: "scoop.bucket", help: "local clone of the scoop bucket scoop publishes manifests to"},
+	{key: "build.targets", help: "os/arch pairs built by build, e.g. linux/amd64,windows/amd64; read from .goreleaser.yaml when not set"},
+}
+
+// find a setting by its key
+func findSetting(key string) (Setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// get the path of the global config file
+func getGlobalConfigPath() (string, error) {
+	dir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// read a toml config file, a missing file is the same as an empty one
+func readConfigFile(path string) (map[string]interface{}, error) {
+
+	values := map[string]interface{}{}
+
+	_, err := toml.DecodeFile(path, &values)
+	if os.IsNotExist(err) {
+		return map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// look up a dotted key such as init.host in a decoded config file
+func lookupConfigValue(values map[string]interface{}, key string) (string, bool) {
+
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		v, ok := values[part]
+		if !ok {
+			return "", false
+		}
+		if i == len(parts)-1 {
+			switch v := v.(type) {
+			case string:
+				return v, true
+			case []interface{}:
+				items := make([]string, len(v))
+				for j, item := range v {
+					items[j] = fmt.Sprint(item)
+				}
+				return strings.Join(items, ","), true
+			default:
+				return fmt.Sprint(v), true
+			}
+		}
+		values, ok = v.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// store a dotted key such as init.host in a decoded config file
+func storeConfigValue(values map[string]interface{}, key string, value string) {
+
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := values[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			values[part] = next
+		}
+		values = next
+	}
+	values[parts[len(parts)-1]] = value
+}
+
+// config files that did not parse and were already reported
+var brokenConfigFiles = map[string]bool{}
+
+// read a config file settings are looked up in
+// a file that does not parse is reported once and then skipped
+func readSettingsFile(path string) map[string]interface{} {
+
+	values, err := readConfigFile(path)
+	if err != nil {
+		if !brokenConfigFiles[path] {
+			brokenConfigFiles[path] = true
+			fmt.Print("💥 ")
+			color.Red(err.Error())
+			color.White("💬  The settings in " + path + " are ignored until it is fixed.")
+		}
+		return nil
+	}
+	return values
+}
+
+// get the effective value of a setting and where it came from
+// precedence is flag > env > project > global > default
+func getSetting(key string, flag string) (string, string) {
+
+	if flag != "" {
+		return flag, "flag"
+	}
+
+	s, _ := findSetting(key)
+
+	if s.env != "" {
+		if v := os.Getenv(s.env); v != "" {
+			return v, "env " + s.env
+		}
+	}
+
+	if v, ok := lookupConfigValue(readSettingsFile(projectConfigFile), key); ok {
+		return v, "project " + projectConfigFile
+	}
+
+	if path, err := getGlobalConfigPath(); err == nil {
+		if v, ok := lookupConfigValue(readSettingsFile(path), key); ok {
+			return v, "global " + path
+		}
+	}
+
+	if s.def != "" {
+		return s.def, "default"
+	}
+	return "", "unset"
+}
+
+// get the effective value of a setting, ignoring where it came from
+func getSettingValue(key string, flag string) string {
+	v, _ := getSetting(key, flag)
+	return v
+}
+
+// get the config file to write to
+func getConfigPath(project bool) (string, error) {
+	if project {
+		return projectConfigFile, nil
+	}
+	return getGlobalConfigPath()
+}
+
+// print the value and source of a single setting
+func configGet(key string) error {
+
+	s, ok := findSetting(key)
+	if !ok {
+		fmt.Print("💥 ")
+		color.Red("Unknown setting " + key + ". Run gopher config list to see all settings.")
+		return fmt.Errorf("unknown setting: %s", key)
+	}
+
+	value, source := getSetting(s.key, "")
+	fmt.Println(value)
+	color.White("💬 " + s.key + " comes from " + source)
+	return nil
+}
+
+// write a setting into the global or project config file
+func configSet(key string, value string, project bool) error {
+
+	if _, ok := findSetting(key); !ok {
+		fmt.Print("💥 ")
+		color.Red("Unknown setting " + key + ". Run gopher config list to see all settings.")
+		return fmt.Errorf("unknown setting: %s", key)
+	}
+
+	path, err := getConfigPath(project)
+	if err != nil {
+		return err
+	}
+
+	values, err := readConfigFile(path)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return err
+	}
+	storeConfigValue(values, key, value)
+
+	if dir := filepath.Dir(path); dir != "." {
+		err = os.MkdirAll(dir, 0755)
+		if err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error creating " + path)
+		color.Red(err.Error())
+		return err
+	}
+	defer file.Close()
+
+	err = toml.NewEncoder(file).Encode(values)
+	if err != nil {
+		return err
+	}
+
+	color.Green("✔  " + key + " set to " + value + " in " + path)
+	return nil
+}
+
+// print every setting with its effective value and source
+func configList() error {
+
+	keys := make([]string, 0, len(settings))
+	for _, s := range settings {
+		keys = append(keys, s.key)
+	}
+	sort.Strings(keys)
+
+	fmt.Println()
+	color.White("📝 Gopher settings:")
+	for _, key := range keys {
+		s, _ := findSetting(key)
+		value, source := getSetting(key, "")
+		color.White(fmt.Sprintf("  %-20s %-24s (%s)", key, value, source))
+		color.White(fmt.Sprintf("  %-20s %s", "", s.help))
+	}
+	fmt.Println()
+
+	if path, err := getGlobalConfigPath(); err == nil {
+		color.White("💬 Global config:  " + path)
+	}
+	color.White("💬 Project config: " + projectConfigFile)
+	return nil
+}
+
+// open the global or project config file in the user's editor
+func configEdit(project bool) error {
+
+	path, err := getConfigPath(project)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if dir := filepath.Dir(path); dir != "." {
+			os.MkdirAll(dir, 0755)
+		}
+		err = os.WriteFile(path, []byte("# gopher configuration, run gopher config list to see all settings\n"), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	color.Cyan("Opening " + path + " in " + editor + "...")
+	args := append(strings.Fields(editor), path)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return err
+	}
+
+	// make sure the edited file still parses
+	_, err = readConfigFile(path)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return err
+	}
+
+	color.Green("✔  " + path + " saved.")
+	return nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestGetSetting(t *testing.T) {
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	os.MkdirAll(filepath.Join(configDir, "gopher"), 0755)
+
+	global := "username = \"globaluser\"\n\n[init]\nhost = \"gitlab.com\"\nbranch = \"trunk\"\n"
+	os.WriteFile(filepath.Join(configDir, "gopher", "config.toml"), []byte(global), 0644)
+	os.WriteFile(projectConfigFile, []byte("[init]\nhost = \"codeberg.org\"\n"), 0644)
+
+	testCases := []struct {
+		name   string
+		key    string
+		flag   string
+		env    string
+		value  string
+		source string
+	}{
+		{"flag-wins", "username", "flaguser", "envuser", "flaguser", "flag"},
+		{"env-over-files", "username", "", "envuser", "envuser", "env GOPHER_USERNAME"},
+		{"global-file", "username", "", "", "globaluser", "global"},
+		{"project-over-global", "init.host", "", "", "codeberg.org", "project .gopher.toml"},
+		{"global-over-default", "init.branch", "", "", "trunk", "global"},
+		{"default", "init.origin_style", "", "", "ssh", "default"},
+		{"unset", "init.license", "", "", "", "unset"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("GOPHER_USERNAME", tc.env)
+			value, source := getSetting(tc.key, tc.flag)
+			if value != tc.value {
+				t.Errorf("expected value %q, got %q", tc.value, value)
+			}
+			if !strings.HasPrefix(source, tc.source) {
+				t.Errorf("expected source %q, got %q", tc.source, source)
+			}
+		})
+	}
+}
+
+func TestGetSettingMalformedFile(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	os.MkdirAll(filepath.Join(configDir, "gopher"), 0755)
+	global := filepath.Join(configDir, "gopher", "config.toml")
+
+	testCases := []struct {
+		name    string
+		project string
+		global  string
+		file    string
+		value   string
+	}{
+		{"project", "[init\nhost = \"codeberg.org\"\n", "[init]\nhost = \"gitlab.com\"\n", projectConfigFile, "gitlab.com"},
+		{"global", "", "[init]\nhost = codeberg.org\n", global, "github.com"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buff.Reset()
+			brokenConfigFiles = map[string]bool{}
+			os.WriteFile(projectConfigFile, []byte(tc.project), 0644)
+			os.WriteFile(global, []byte(tc.global), 0644)
+
+			// the broken file is skipped, the next one down still counts
+			value, _ := getSetting("init.host", "")
+			if value != tc.value {
+				t.Errorf("expected value %q, got %q", tc.value, value)
+			}
+			if !strings.Contains(buff.String(), "error reading "+tc.file) {
+				t.Errorf("expected a warning naming %s, got %q", tc.file, buff.String())
+			}
+
+			// and it is only reported once
+			buff.Reset()
+			getSetting("init.branch", "")
+			if strings.Contains(buff.String(), tc.file) {
+				t.Errorf("expected the warning only once, got %q", buff.String())
+			}
+		})
+	}
+}
+
+func TestConfigSet(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("GOPHER_USERNAME", "")
+
+	t.Run("global", func(t *testing.T) {
+		if err := configSet("username", "globaluser", false); err != nil {
+			t.Fatalf("configSet() failed: %v", err)
+		}
+		if err := configSet("init.host", "gitlab.com", false); err != nil {
+			t.Fatalf("configSet() failed: %v", err)
+		}
+
+		// setting a second key must keep the first one
+		if got := getSettingValue("username", ""); got != "globaluser" {
+			t.Errorf("expected username %q, got %q", "globaluser", got)
+		}
+		if got := getSettingValue("init.host", ""); got != "gitlab.com" {
+			t.Errorf("expected init.host %q, got %q", "gitlab.com", got)
+		}
+	})
+
+	t.Run("project", func(t *testing.T) {
+		if err := configSet("init.host", "codeberg.org", true); err != nil {
+			t.Fatalf("configSet() failed: %v", err)
+		}
+		if _, err := os.Stat(projectConfigFile); err != nil {
+			t.Fatalf("%s was not created: %v", projectConfigFile, err)
+		}
+		value, source := getSetting("init.host", "")
+		if value != "codeberg.org" || !strings.HasPrefix(source, "project") {
+			t.Errorf("expected codeberg.org from the project file, got %q from %q", value, source)
+		}
+	})
+
+	t.Run("unknown-key", func(t *testing.T) {
+		if err := configSet("no.such.key", "value", false); err == nil {
+			t.Error("expected an error for an unknown key, got nil")
+		}
+		if err := configGet("no.such.key"); err == nil {
+			t.Error("expected an error for an unknown key, got nil")
+		}
+	})
+
+	t.Run("invalid-file", func(t *testing.T) {
+		os.WriteFile(projectConfigFile, []byte("this is not = = toml"), 0644)
+		if err := configSet("username", "someone", true); err == nil {
+			t.Error("expected an error for an invalid config file, got nil")
+		}
+	})
+}
+
+func TestCreateProjectConfig(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	tmpBinDir := t.TempDir()
+	createMockExecutable(t, tmpBinDir, "go")
+	t.Setenv("PATH", tmpBinDir)
+
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("GOPHER_USERNAME", "")
+	os.MkdirAll(filepath.Join(configDir, "gopher"), 0755)
+	global := "username = \"configuser\"\n\n[init]\nhost = \"gitlab.com\"\nlicense = \"mit\"\n"
+	os.WriteFile(filepath.Join(configDir, "gopher", "config.toml"), []byte(global), 0644)
+
+	err := createProject("configproject", InitOptions{no_git: true, no_goreleaser: true})
+	if err != nil {
+		t.Fatalf("createProject() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "configproject", "go.mod"))
+	if err != nil {
+		t.Fatalf("go.mod was not created: %v", err)
+	}
+	if !strings.Contains(string(content), "gitlab.com/configuser/configproject") {
+		t.Errorf("expected the module to use the configured host and username, got %q", string(content))
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "configproject", "LICENSE")); err != nil {
+		t.Errorf("expected the configured license to be added: %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// the debian architecture of a target
+func debArch(t Target) (string, bool) {
+	switch t.goarch {
+	case "amd64", "arm64":
+		return t.goarch, true
+	case "386":
+		return "i386", true
+	case "arm":
+		if t.goarm == "6" {
+			return "armel", true
+		}
+		return "armhf", true
+	}
+	return "", false
+}
+
+// debian keeps the license in the copyright file of the package docs
+func debLicensePath(name string, file string) string {
+	return "/usr/share/doc/" + name + "/copyright"
+}
+
+// debian package files are named name_version_arch.deb
+func debFileName(p LinuxPackage) string {
+	return p.name + "_" + p.version + "_" + p.arch + ".deb"
+}
+
+// the control file describing the package to dpkg
+func debControl(p LinuxPackage) string {
+
+	var size int64
+	for _, f := range p.files {
+		size += int64(len(f.data))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Package: %s\n", p.name)
+	fmt.Fprintf(&b, "Version: %s\n", p.version)
+	fmt.Fprintf(&b, "Architecture: %s\n", p.arch)
+	fmt.Fprintf(&b, "Maintainer: %s\n", p.maintainer)
+	// in KiB, rounded up
+	fmt.Fprintf(&b, "Installed-Size: %d\n", (size+1023)/1024)
+	fmt.Fprintf(&b, "Section: utils\n")
+	fmt.Fprintf(&b, "Priority: optional\n")
+	if p.homepage != "" {
+		fmt.Fprintf(&b, "Homepage: %s\n", p.homepage)
+	}
+	fmt.Fprintf(&b, "Description: %s\n", p.description)
+	return b.String()
+}
+
+// write a gzipped tarball of files, adding the folders they are in
+// the names are relative to the root, e.g. ./usr/bin/tool
+func debTarball(files map[string]PackageFile, p LinuxPackage) ([]byte, error) {
+
+	var names []string
+	dirs := map[string]bool{"/": true}
+	for name := range files {
+		names = append(names, name)
+		for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			dirs[dir+"/"] = true
+		}
+	}
+	for dir := range dirs {
+		names = append(names, dir)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	gw := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gw)
+
+	for _, name := range names {
+		h := &tar.Header{Name: "." + name, Mode: 0755, ModTime: p.mtime, Uname: "root", Gname: "root", Format: tar.FormatGNU}
+		f, ok := files[name]
+		if ok {
+			h.Typeflag = tar.TypeReg
+			h.Mode = f.mode
+			h.Size = int64(len(f.data))
+		} else {
+			h.Typeflag = tar.TypeDir
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			return nil, err
+		}
+		if ok {
+			if _, err := tw.Write(f.data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// write one member of an ar archive, the container a .deb is
+func writeArEntry(b *bytes.Buffer, name string, data []byte, mtime int64) {
+	fmt.Fprintf(b, "%-16s%-12d%-6d%-6d%-8s%-10d`\n", name, mtime, 0, 0, "100644", len(data))
+	b.Write(data)
+	// members start on an even offset
+	if len(data)%2 == 1 {
+		b.WriteByte('\n')
+	}
+}
+
+// build a .deb package, see https://manpages.debian.org/deb.5
+func buildDeb(p LinuxPackage) ([]byte, error) {
+
+	data := map[string]PackageFile{}
+	var md5sums strings.Builder
+	for _, f := range p.files {
+		data[f.path] = f
+		fmt.Fprintf(&md5sums, "%x  %s\n", md5.Sum(f.data), strings.TrimPrefix(f.path, "/"))
+	}
+
+	control := map[string]PackageFile{
+		"/control": {mode: 0644, data: []byte(debControl(p))},
+		"/md5sums": {mode: 0644, data: []byte(md5sums.String())},
+	}
+
+	control_tar, err := debTarball(control, p)
+	if err != nil {
+		return nil, err
+	}
+	data_tar, err := debTarball(data, p)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteString("!<arch>\n")
+	writeArEntry(&b, "debian-binary", []byte("2.0\n"), p.mtime.Unix())
+	writeArEntry(&b, "control.tar.gz", control_tar, p.mtime.Unix())
+	writeArEntry(&b, "data.tar.gz", data_tar, p.mtime.Unix())
+	return b.Bytes(), nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDebArch(t *testing.T) {
+	testCases := []struct {
+		target   Target
+		expected string
+	}{
+		{Target{goos: "linux", goarch: "amd64"}, "amd64"},
+		{Target{goos: "linux", goarch: "386"}, "i386"},
+		{Target{goos: "linux", goarch: "arm64"}, "arm64"},
+		{Target{goos: "linux", goarch: "arm", goarm: "6"}, "armel"},
+		{Target{goos: "linux", goarch: "arm", goarm: "7"}, "armhf"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.target.String(), func(t *testing.T) {
+			if got, _ := debArch(tc.target); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuildDeb(t *testing.T) {
+
+	pkg := LinuxPackage{
+		name:        "tool",
+		version:     "2.0.0",
+		arch:        "amd64",
+		maintainer:  "Jane Doe <jane@example.com>",
+		homepage:    "https://github.com/user/tool",
+		license:     "MIT",
+		description: "A tool that does things.",
+		mtime:       time.Unix(1700000000, 0),
+		files: []PackageFile{
+			{path: "/usr/bin/tool", mode: 0755, data: []byte("binary")},
+			{path: debLicensePath("tool", "LICENSE"), mode: 0644, data: []byte("MIT License\n"), license: true},
+		},
+	}
+
+	content, err := buildDeb(pkg)
+	if err != nil {
+		t.Fatalf("buildDeb() failed: %v", err)
+	}
+
+	members := readAr(t, content)
+	if string(members["debian-binary"]) != "2.0\n" {
+		t.Errorf("expected debian-binary 2.0, got %q", string(members["debian-binary"]))
+	}
+
+	control := readTarGz(t, members["control.tar.gz"])
+	expected := `Package: tool
+Version: 2.0.0
+Architecture: amd64
+Maintainer: Jane Doe <jane@example.com>
+Installed-Size: 1
+Section: utils
+Priority: optional
+Homepage: https://github.com/user/tool
+Description: A tool that does things.
+`
+	if control["./control"] != expected {
+		t.Errorf("expected control:\n%s\ngot:\n%s", expected, control["./control"])
+	}
+	if !strings.Contains(control["./md5sums"], "  usr/bin/tool\n") {
+		t.Errorf("expected the binary in md5sums, got %q", control["./md5sums"])
+	}
+
+	data := readTarGz(t, members["data.tar.gz"])
+	if data["./usr/bin/tool"] != "binary" {
+		t.Errorf("expected the binary in /usr/bin, got %v", data)
+	}
+	if data["./usr/share/doc/tool/copyright"] != "MIT License\n" {
+		t.Errorf("expected the license in the copyright file, got %v", data)
+	}
+
+	// let dpkg have a look too when it is around
+	if _, err := exec.LookPath("dpkg-deb"); err == nil {
+		file := filepath.Join(t.TempDir(), debFileName(pkg))
+		os.WriteFile(file, content, 0644)
+		out, err := exec.Command("dpkg-deb", "--field", file, "Package", "Version").CombinedOutput()
+		if err != nil {
+			t.Fatalf("dpkg-deb could not read the package: %v\n%s", err, out)
+		}
+		if string(out) != "Package: tool\nVersion: 2.0.0\n" {
+			t.Errorf("unexpected dpkg-deb output: %q", string(out))
+		}
+	}
+}
+
+// read the members of an ar archive
+func readAr(t *testing.T, content []byte) map[string][]byte {
+	if !bytes.HasPrefix(content, []byte("!<arch>\n")) {
+		t.Fatalf("not an ar archive")
+	}
+	members := map[string][]byte{}
+	rest := content[8:]
+	for len(rest) >= 60 {
+		name := strings.TrimSpace(string(rest[:16]))
+		size, err := strconv.Atoi(strings.TrimSpace(string(rest[48:58])))
+		if err != nil {
+			t.Fatalf("invalid ar header: %q", string(rest[:60]))
+		}
+		members[name] = rest[60 : 60+size]
+		rest = rest[60+size+size%2:]
+	}
+	return members
+}
+
+// read the regular files of a gzipped tarball
+func readTarGz(t *testing.T, content []byte) map[string]string {
+	gr, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("failed to read the tarball: %v", err)
+	}
+	tr := tar.NewReader(gr)
+	files := map[string]string{}
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read the tarball: %v", err)
+		}
+		if h.Typeflag == tar.TypeReg {
+			data, _ := io.ReadAll(tr)
+			files[h.Name] = string(data)
+		}
+	}
+	return files
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// where the release artifacts are written
+const distDir = "dist"
+
+// what the package manifests need to know about the project
+type ReleaseInfo struct {
+	name     string
+	owner    string
+	version  string
+	homepage string
+	license  string
+	forge    Forge
+}
+
+// the download url of a release file of this version
+func (r ReleaseInfo) downloadURL(file string) string {
+	return r.forge.releaseURL(r.owner, r.name, r.version, file)
+}
+
+// gather the project details the package manifests are made from
+// the dist folder must exist, the manifests are built from the release artifacts
+func getReleaseInfo() (ReleaseInfo, error) {
+
+	// check if the dist/ folder exists in the project directory and if not exit
+	if _, err := os.Stat(distDir); os.IsNotExist(err) {
+		// warn
+		color.Yellow("⚠  dist/ folder does not exist in the project directory.")
+		color.White("💬  Make sure you have built the project using gopher release")
+		return ReleaseInfo{}, err
+	}
+
+	var info ReleaseInfo
+
+	color.Cyan("Getting module string from go.mod file...")
+	uri, em := getModule()
+	if em != nil {
+		return ReleaseInfo{}, em
+	}
+
+	// check if the module string is a uri
+	host, owner, module_name := parseModule(uri)
+	info.forge = getForge(host)
+
+	if owner != "" {
+		info.name = module_name
+		info.owner = owner
+	} else {
+		info.name = uri
+		// check if the username is in an environment variable or config file
+		color.Cyan("Looking up the username setting...")
+		info.owner = getSettingValue("username", "")
+
+		if info.owner == "" {
+			color.Yellow("⚠  username is not set in GOPHER_USERNAME or the gopher config.")
+			// ask for the username since it's not in the module string
+			var ep error
+			info.owner, ep = prompt("Enter your " + info.forge.title + " username and press [ENTER]: ")
+			if ep != nil {
+				return ReleaseInfo{}, ep
+			}
+		}
+	}
+
+	color.Blue("🆗 Got the project name: " + info.name)
+	color.Blue("🆗 Got your " + info.forge.title + " username: " + info.owner)
+
+	color.Cyan("Getting version from gopher.go file...")
+	mainfile, en := getMainFileName()
+	if en != nil {
+		return ReleaseInfo{}, en
+	}
+
+	var ev error
+	info.version, ev = getVersion(mainfile + ".go")
+	if ev != nil {
+		return ReleaseInfo{}, ev
+	}
+
+	color.Blue("🆗 Got the project version: " + info.version)
+
+	color.Cyan("Creating the homepage url...")
+	info.homepage = info.forge.homepage(info.owner, info.name)
+
+	color.Blue("🆗 Homepage url: " + info.homepage)
+
+	color.Cyan("Detecting the project license...")
+	info.license = detectLicense()
+	if info.license != "" {
+		color.Blue("🆗 License: " + info.license)
+	}
+
+	return info, nil
+}
+
+// the person packaging the project, as "Name <email>" from git config
+// returns whichever part is set, or an empty string if neither is
+func getMaintainer() string {
+
+	name, email := getGitAuthor(), getGitEmail()
+	switch {
+	case name != "" && email != "":
+		return name + " <" + email + ">"
+	case email != "":
+		return "<" + email + ">"
+	}
+	return name
+}
+
+// a file produced by a release build
+type Artifact struct {
+	name string
+	size int64
+}
+
+// list the files at the top of the dist folder, sorted by name
+// the per-target build folders goreleaser leaves behind are skipped
+func getDistArtifacts() ([]Artifact, error) {
+
+	entries, err := os.ReadDir(distDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []Artifact
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, Artifact{name: entry.Name(), size: info.Size()})
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].name < artifacts[j].name })
+	return artifacts, nil
+}
+
+// format a file size the way people read it, e.g. 1.5 MB
+func formatSize(size int64) string {
+
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value := float64(size) / unit
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		if value < unit || suffix == "GB" {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return ""
+}
+
+// print the artifacts in the dist folder with their sizes
+func listDistArtifacts() error {
+
+	artifacts, err := getDistArtifacts()
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error reading the " + distDir + " folder")
+		color.Red(err.Error())
+		return err
+	}
+
+	if len(artifacts) == 0 {
+		color.Yellow("⚠  No artifacts found in " + distDir + ".")
+		return nil
+	}
+
+	fmt.Println()
+	color.White("📦 Artifacts in " + distDir + ":")
+	for _, a := range artifacts {
+		color.White(fmt.Sprintf("  %-50s %10s", filepath.Join(distDir, a.name), formatSize(a.size)))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// the first paragraph of the project readme, which is usually what the project does
+// headings, badges and html are skipped, returns an empty string if there is none
+func getProjectDescription() string {
+
+	for _, f := range []string{"README.md", "README", "readme.md"} {
+		content, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+
+		var paragraph []string
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				if len(paragraph) > 0 {
+					break
+				}
+				continue
+			}
+			if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[![") || strings.HasPrefix(line, "![") || strings.HasPrefix(line, "<") {
+				if len(paragraph) > 0 {
+					break
+				}
+				continue
+			}
+			paragraph = append(paragraph, line)
+		}
+		return strings.Join(paragraph, " ")
+	}
+	return ""
+}
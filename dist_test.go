@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestFormatSize(t *testing.T) {
+	testCases := []struct {
+		size     int64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GB"},
+		{2048 * 1024 * 1024 * 1024, "2048.0 GB"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			if got := formatSize(tc.size); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetDistArtifacts(t *testing.T) {
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	t.Run("no-dist", func(t *testing.T) {
+		if _, err := getDistArtifacts(); err == nil {
+			t.Error("expected an error without a dist folder, got nil")
+		}
+	})
+
+	t.Run("files-only", func(t *testing.T) {
+		os.MkdirAll(filepath.Join(distDir, "tool_linux_amd64_v1"), 0755)
+		os.WriteFile(filepath.Join(distDir, "tool_linux_amd64_v1", "tool"), []byte("binary"), 0755)
+		os.WriteFile(filepath.Join(distDir, "tool_1.0.0_Windows_x86_64.zip"), []byte("zip"), 0644)
+		os.WriteFile(filepath.Join(distDir, "tool_1.0.0_checksums.txt"), []byte("sums\n"), 0644)
+
+		artifacts, err := getDistArtifacts()
+		if err != nil {
+			t.Fatalf("getDistArtifacts() failed: %v", err)
+		}
+		expected := []Artifact{
+			{name: "tool_1.0.0_Windows_x86_64.zip", size: 3},
+			{name: "tool_1.0.0_checksums.txt", size: 5},
+		}
+		if len(artifacts) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, artifacts)
+		}
+		for i := range expected {
+			if artifacts[i] != expected[i] {
+				t.Errorf("expected %v, got %v", expected[i], artifacts[i])
+			}
+		}
+	})
+}
+
+func TestGetProjectDescription(t *testing.T) {
+	testCases := []struct {
+		name     string
+		readme   string
+		expected string
+	}{
+		{"no-readme", "", ""},
+		{"title-only", "# tool\n", ""},
+		{"first-paragraph", "# tool\n\nA tool that does\nthings.\n\nMore text.\n", "A tool that does things."},
+		{"badges", "# tool\n\n[![build](https://example.com/badge.svg)](https://example.com)\n\nDoes things.\n", "Does things."},
+		{"stops-at-heading", "Does things.\n## Usage\n", "Does things."},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			originalDir, _ := os.Getwd()
+			os.Chdir(tmpDir)
+			defer os.Chdir(originalDir)
+
+			if tc.readme != "" {
+				os.WriteFile("README.md", []byte(tc.readme), 0644)
+			}
+			if got := getProjectDescription(); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetReleaseInfoPrompt(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origInteractive := isInteractive
+	isInteractive = func() bool { return false }
+	defer func() { isInteractive = origInteractive }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	os.Mkdir(distDir, 0755)
+	os.WriteFile("go.mod", []byte("module tool"), 0644)
+
+	if _, err := getReleaseInfo(); err == nil {
+		t.Fatal("expected an error without a username and a terminal, got nil")
+	}
+
+	expected := "Enter your " + getForge("").title + " username"
+	if !strings.Contains(buff.String(), expected) {
+		t.Errorf("expected the prompt to name the forge, got %q", buff.String())
+	}
+}
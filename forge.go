@@ -0,0 +1,148 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+)
+
+// the kinds of git hosting services gopher knows how to talk about
+const (
+	forgeGitHub = "github"
+	forgeGitLab = "gitlab"
+	forgeGitea  = "gitea"
+)
+
+// a git hosting service, either a public one or a self-hosted instance
+type Forge struct {
+	kind  string
+	title string
+	host  string
+}
+
+// figure out which forge is running on the given host
+// unknown hosts are assumed to be self-hosted Gitea unless the forge setting says otherwise
+func getForge(host string) Forge {
+
+	if host == "" {
+		host = "github.com"
+	}
+	h := strings.ToLower(host)
+
+	switch {
+	case h == "github.com":
+		return Forge{kind: forgeGitHub, title: "Github", host: host}
+	case h == "gitlab.com":
+		return Forge{kind: forgeGitLab, title: "Gitlab", host: host}
+	case h == "codeberg.org":
+		return Forge{kind: forgeGitea, title: "Codeberg", host: host}
+	}
+
+	switch strings.ToLower(getSettingValue("forge", "")) {
+	case forgeGitHub:
+		return Forge{kind: forgeGitHub, title: "Github", host: host}
+	case forgeGitLab:
+		return Forge{kind: forgeGitLab, title: "Gitlab", host: host}
+	case forgeGitea:
+		return Forge{kind: forgeGitea, title: "Gitea", host: host}
+	}
+
+	switch {
+	case strings.Contains(h, "github"):
+		return Forge{kind: forgeGitHub, title: "Github", host: host}
+	case strings.Contains(h, "gitlab"):
+		return Forge{kind: forgeGitLab, title: "Gitlab", host: host}
+	}
+
+	return Forge{kind: forgeGitea, title: "Gitea", host: host}
+}
+
+// split a module path into host, owner and project name
+// the owner can span several path elements, e.g. gitlab subgroups
+func parseModule(module string) (host string, owner string, name string) {
+
+	parts := strings.Split(strings.Trim(module, "/"), "/")
+	name = parts[len(parts)-1]
+
+	if len(parts) == 1 {
+		return "", "", name
+	}
+
+	// the first element is a host only if it looks like a domain name
+	if len(parts) == 2 || !strings.Contains(parts[0], ".") {
+		return "", strings.Join(parts[:len(parts)-1], "/"), name
+	}
+
+	return parts[0], strings.Join(parts[1:len(parts)-1], "/"), name
+}
+
+// the go module path of a project hosted on this forge
+func (f Forge) module(owner string, name string) string {
+	return f.host + "/" + owner + "/" + name
+}
+
+// the ssh git origin of a project
+func (f Forge) sshOrigin(owner string, name string) string {
+	return "git@" + f.host + ":" + owner + "/" + name + ".git"
+}
+
+// the https git origin of a project
+func (f Forge) httpsOrigin(owner string, name string) string {
+	return "https://" + f.host + "/" + owner + "/" + name + ".git"
+}
+
+// the web page of a project
+func (f Forge) homepage(owner string, name string) string {
+	return "https://" + f.host + "/" + owner + "/" + name
+}
+
+// the page listing the releases of a project
+func (f Forge) releasesPage(owner string, name string) string {
+	if f.kind == forgeGitLab {
+		return f.homepage(owner, name) + "/-/releases"
+	}
+	return f.homepage(owner, name) + "/releases"
+}
+
+// the download url of a file attached to the release of the given version
+func (f Forge) releaseURL(owner string, name string, version string, file string) string {
+	if f.kind == forgeGitLab {
+		return f.homepage(owner, name) + "/-/releases/v" + version + "/downloads/" + file
+	}
+	return f.homepage(owner, name) + "/releases/download/v" + version + "/" + file
+}
+
+// pulls the version out of a release tag for scoop, prereleases such as v1.2.0-rc.1 included
+const scoopVersionRegex = `v([\d.]+(?:-[0-9A-Za-z.-]+)?)`
+
+// the scoop checkver rule for a project
+func (f Forge) checkver(owner string, name string) interface{} {
+	switch f.kind {
+	case forgeGitHub:
+		return "github"
+	case forgeGitLab:
+		project := url.PathEscape(owner + "/" + name)
+		return map[string]string{
+			"url":      "https://" + f.host + "/api/v4/projects/" + project + "/releases",
+			"jsonpath": "$[0].tag_name",
+			"regex":    scoopVersionRegex,
+		}
+	default:
+		return map[string]string{
+			"url":      "https://" + f.host + "/api/v1/repos/" + owner + "/" + name + "/releases/latest",
+			"jsonpath": "$.tag_name",
+			"regex":    scoopVersionRegex,
+		}
+	}
+}
+
+// the environment variable goreleaser reads the api token from
+func (f Forge) tokenEnv() string {
+	switch f.kind {
+	case forgeGitLab:
+		return "GITLAB_TOKEN"
+	case forgeGitea:
+		return "GITEA_TOKEN"
+	default:
+		return "GITHUB_TOKEN"
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestGetForge(t *testing.T) {
+	testCases := []struct {
+		host  string
+		env   string
+		kind  string
+		title string
+	}{
+		{"github.com", "", forgeGitHub, "Github"},
+		{"", "", forgeGitHub, "Github"},
+		{"gitlab.com", "", forgeGitLab, "Gitlab"},
+		{"codeberg.org", "", forgeGitea, "Codeberg"},
+		{"gitlab.example.com", "", forgeGitLab, "Gitlab"},
+		{"github.example.com", "", forgeGitHub, "Github"},
+		{"git.example.com", "", forgeGitea, "Gitea"},
+		{"git.example.com", "gitlab", forgeGitLab, "Gitlab"},
+		{"github.com", "gitea", forgeGitHub, "Github"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.host+"-"+tc.env, func(t *testing.T) {
+			t.Setenv("GOPHER_FORGE", tc.env)
+			f := getForge(tc.host)
+			if f.kind != tc.kind {
+				t.Errorf("expected kind %q, got %q", tc.kind, f.kind)
+			}
+			if f.title != tc.title {
+				t.Errorf("expected title %q, got %q", tc.title, f.title)
+			}
+		})
+	}
+}
+
+func TestParseModule(t *testing.T) {
+	testCases := []struct {
+		module string
+		host   string
+		owner  string
+		name   string
+	}{
+		{"github.com/user/repo", "github.com", "user", "repo"},
+		{"gitlab.com/group/sub/repo", "gitlab.com", "group/sub", "repo"},
+		{"user/repo", "", "user", "repo"},
+		{"repo", "", "", "repo"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.module, func(t *testing.T) {
+			host, owner, name := parseModule(tc.module)
+			if host != tc.host || owner != tc.owner || name != tc.name {
+				t.Errorf("expected (%q, %q, %q), got (%q, %q, %q)", tc.host, tc.owner, tc.name, host, owner, name)
+			}
+		})
+	}
+}
+
+func TestForgeURLs(t *testing.T) {
+
+	t.Setenv("GOPHER_FORGE", "")
+
+	testCases := []struct {
+		host     string
+		ssh      string
+		https    string
+		homepage string
+		releases string
+		download string
+		token    string
+	}{
+		{
+			"github.com",
+			"git@github.com:user/repo.git",
+			"https://github.com/user/repo.git",
+			"https://github.com/user/repo",
+			"https://github.com/user/repo/releases",
+			"https://github.com/user/repo/releases/download/v1.0.0/repo.zip",
+			"GITHUB_TOKEN",
+		},
+		{
+			"gitlab.com",
+			"git@gitlab.com:user/repo.git",
+			"https://gitlab.com/user/repo.git",
+			"https://gitlab.com/user/repo",
+			"https://gitlab.com/user/repo/-/releases",
+			"https://gitlab.com/user/repo/-/releases/v1.0.0/downloads/repo.zip",
+			"GITLAB_TOKEN",
+		},
+		{
+			"git.example.com",
+			"git@git.example.com:user/repo.git",
+			"https://git.example.com/user/repo.git",
+			"https://git.example.com/user/repo",
+			"https://git.example.com/user/repo/releases",
+			"https://git.example.com/user/repo/releases/download/v1.0.0/repo.zip",
+			"GITEA_TOKEN",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.host, func(t *testing.T) {
+			f := getForge(tc.host)
+			if got := f.module("user", "repo"); got != tc.host+"/user/repo" {
+				t.Errorf("module: expected %q, got %q", tc.host+"/user/repo", got)
+			}
+			if got := f.sshOrigin("user", "repo"); got != tc.ssh {
+				t.Errorf("ssh origin: expected %q, got %q", tc.ssh, got)
+			}
+			if got := f.httpsOrigin("user", "repo"); got != tc.https {
+				t.Errorf("https origin: expected %q, got %q", tc.https, got)
+			}
+			if got := f.homepage("user", "repo"); got != tc.homepage {
+				t.Errorf("homepage: expected %q, got %q", tc.homepage, got)
+			}
+			if got := f.releasesPage("user", "repo"); got != tc.releases {
+				t.Errorf("releases page: expected %q, got %q", tc.releases, got)
+			}
+			if got := f.releaseURL("user", "repo", "1.0.0", "repo.zip"); got != tc.download {
+				t.Errorf("release url: expected %q, got %q", tc.download, got)
+			}
+			if got := f.tokenEnv(); got != tc.token {
+				t.Errorf("token env: expected %q, got %q", tc.token, got)
+			}
+		})
+	}
+}
+
+func TestGenerateScoopFileForge(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+	color.NoColor = true
+
+	origStdout := os.Stdout
+	origStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	os.Stderr = w
+	defer func() {
+		os.Stdout = origStdout
+		os.Stderr = origStderr
+	}()
+
+	t.Setenv("GOPHER_FORGE", "")
+
+	testCases := []struct {
+		module   string
+		url      string
+		checkver string
+	}{
+		{
+			"gitlab.com/group/tool",
+			"https://gitlab.com/group/tool/-/releases/v1.0.0/downloads/tool_1.0.0_Windows_x86_64.zip",
+			`"jsonpath": "$[0].tag_name"`,
+		},
+		{
+			"git.example.com/team/tool",
+			"https://git.example.com/team/tool/releases/download/v1.0.0/tool_1.0.0_Windows_x86_64.zip",
+			`"url": "https://git.example.com/api/v1/repos/team/tool/releases/latest"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.module, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			originalDir, _ := os.Getwd()
+			os.Chdir(tmpDir)
+			defer os.Chdir(originalDir)
+
+			os.Mkdir("dist", 0755)
+			os.WriteFile("go.mod", []byte("module "+tc.module), 0644)
+			os.WriteFile("main.go", []byte("package main\nconst version = \"1.0.0\""), 0644)
+			checksum := "abc123  tool_1.0.0_Windows_x86_64.zip"
+			os.WriteFile(filepath.Join("dist", "tool_1.0.0_checksums.txt"), []byte(checksum), 0644)
+
+			if err := generateScoopFile(ScoopOptions{}); err != nil {
+				t.Fatalf("generateScoopFile failed: %v", err)
+			}
+
+			content, _ := os.ReadFile(filepath.Join("dist", "tool.json"))
+			if !strings.Contains(string(content), tc.url) {
+				t.Errorf("expected manifest to contain %q, got %s", tc.url, content)
+			}
+			if !strings.Contains(string(content), tc.checkver) {
+				t.Errorf("expected manifest to contain %q, got %s", tc.checkver, content)
+			}
+		})
+	}
+}
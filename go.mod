@@ -3,13 +3,14 @@ module github.com/maciakl/gopher
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/fatih/color v1.17.0
+	github.com/mattn/go-isatty v0.0.20
 	github.com/otiai10/copy v1.14.0
 )
 
 require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
-	github.com/mattn/go-isatty v0.0.20 // indirect
 	golang.org/x/sync v0.3.0 // indirect
 	golang.org/x/sys v0.20.0 // indirect
 )
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// license texts gopher can add to a project
+//
+//go:embed licenses
+var licenseTexts embed.FS
+
+// a license gopher knows how to write and detect
+type License struct {
+	id          string
+	spdx        string
+	fingerprint []string
+	// whether the text has a copyright line with the year and author
+	copyright bool
+}
+
+// supported licenses, keyed by the name used on the command line
+var licenses = map[string]License{
+	"mit": {
+		id:          "mit",
+		spdx:        "MIT",
+		fingerprint: []string{"Permission is hereby granted, free of charge"},
+		copyright:   true,
+	},
+	"apache-2.0": {
+		id:          "apache-2.0",
+		spdx:        "Apache-2.0",
+		fingerprint: []string{"Apache License", "Version 2.0"},
+		copyright:   true,
+	},
+	"bsd-3-clause": {
+		id:          "bsd-3-clause",
+		spdx:        "BSD-3-Clause",
+		fingerprint: []string{"Redistribution and use in source and binary forms", "Neither the name of"},
+		copyright:   true,
+	},
+	"mpl-2.0": {
+		id:          "mpl-2.0",
+		spdx:        "MPL-2.0",
+		fingerprint: []string{"Mozilla Public License", "2.0"},
+	},
+	"unlicense": {
+		id:          "unlicense",
+		spdx:        "Unlicense",
+		fingerprint: []string{"This is free and unencumbered software released into the public domain"},
+	},
+}
+
+// file names that commonly hold the project license
+var licenseFiles = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING"}
+
+// data made available to the license texts
+type LicenseData struct {
+	Year   string
+	Author string
+}
+
+// the supported license names in a stable order
+func getLicenseIds() []string {
+	ids := make([]string, 0, len(licenses))
+	for id := range licenses {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// get the author name from git config
+func getGitAuthor() string {
+	cmd := exec.Command("git", "config", "user.name")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// get the author email from git config
+func getGitEmail() string {
+	cmd := exec.Command("git", "config", "user.email")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// find the file the project license is in, returns an empty string if there is none
+func findLicenseFile() string {
+	for _, f := range licenseFiles {
+		if _, err := os.Stat(f); err == nil {
+			return f
+		}
+	}
+	return ""
+}
+
+// write the LICENSE file for the given license id into the current directory
+func writeLicense(id string, author string) error {
+
+	license, ok := licenses[strings.ToLower(id)]
+	if !ok {
+		fmt.Print("💥 ")
+		color.Red("Unknown license " + id + ". Use one of: " + strings.Join(getLicenseIds(), ", "))
+		return fmt.Errorf("unknown license: %s", id)
+	}
+
+	text, err := licenseTexts.ReadFile("licenses/" + license.id + ".txt")
+	if err != nil {
+		return err
+	}
+
+	data := LicenseData{
+		Year:   strconv.Itoa(time.Now().Year()),
+		Author: author,
+	}
+
+	t, err := template.New(license.id).Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	var content strings.Builder
+	err = t.Execute(&content, data)
+	if err != nil {
+		return err
+	}
+
+	color.Cyan("Creating LICENSE file (" + license.spdx + ")...")
+	err = os.WriteFile("LICENSE", []byte(content.String()), 0644)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error creating LICENSE file")
+		color.Red(err.Error())
+		return err
+	}
+
+	color.Blue("🆗 LICENSE file created.")
+	return nil
+}
+
+// detect the SPDX identifier of the license in the current directory
+// returns an empty string when there is no license file or it is not recognized
+func detectLicense() string {
+
+	for _, f := range licenseFiles {
+		content, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		text := string(content)
+
+		// an explicit identifier always wins
+		for _, line := range strings.Split(text, "\n") {
+			if _, spdx, ok := strings.Cut(line, "SPDX-License-Identifier:"); ok {
+				return strings.TrimSpace(spdx)
+			}
+		}
+
+		for _, id := range getLicenseIds() {
+			license := licenses[id]
+			found := true
+			for _, phrase := range license.fingerprint {
+				if !strings.Contains(text, phrase) {
+					found = false
+					break
+				}
+			}
+			if found {
+				return license.spdx
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// add a license to an existing project
+func addLicense(id string, author string, force bool) error {
+
+	if !force {
+		for _, f := range licenseFiles {
+			if _, err := os.Stat(f); err == nil {
+				fmt.Print("💥 ")
+				color.Red("The project already has a " + f + " file. Use --force to overwrite it.")
+				return fmt.Errorf("%s already exists", f)
+			}
+		}
+	}
+
+	// licenses without a copyright line have no use for the author
+	license, ok := licenses[strings.ToLower(id)]
+	if ok && !license.copyright {
+		if author != "" {
+			color.Yellow("⚠  The " + license.spdx + " license has no copyright line, the author is not used.")
+		}
+		author = ""
+	} else {
+		if author == "" {
+			color.Cyan("Getting the author name from git config...")
+			author = getGitAuthor()
+		}
+		if author == "" {
+			color.Cyan("Getting the author name from go.mod file...")
+			module, err := getModule()
+			if err != nil {
+				return err
+			}
+			_, author, _ = parseModule(module)
+		}
+		if author == "" {
+			fmt.Print("💥 ")
+			color.Red("Could not determine the author name. Use --author to set it.")
+			return fmt.Errorf("could not determine the author name")
+		}
+		color.Blue("🆗 Author: " + author)
+	}
+
+	err := writeLicense(id, author)
+	if err != nil {
+		return err
+	}
+
+	color.Green("✔  License " + licenses[strings.ToLower(id)].spdx + " added successfully.")
+	return nil
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+func TestWriteLicense(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	for _, id := range getLicenseIds() {
+		t.Run(id, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			originalDir, _ := os.Getwd()
+			os.Chdir(tmpDir)
+			defer os.Chdir(originalDir)
+
+			if err := writeLicense(id, "Jane Doe"); err != nil {
+				t.Fatalf("writeLicense() failed: %v", err)
+			}
+
+			content, err := os.ReadFile("LICENSE")
+			if err != nil {
+				t.Fatalf("LICENSE was not created: %v", err)
+			}
+			if strings.Contains(string(content), "{{") {
+				t.Error("LICENSE contains unrendered template markers")
+			}
+
+			// every license we write must be detected as itself
+			if got := detectLicense(); got != licenses[id].spdx {
+				t.Errorf("expected detected license %q, got %q", licenses[id].spdx, got)
+			}
+		})
+	}
+
+	t.Run("year-and-author", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		if err := writeLicense("MIT", "Jane Doe"); err != nil {
+			t.Fatalf("writeLicense() failed: %v", err)
+		}
+
+		content, _ := os.ReadFile("LICENSE")
+		expected := "Copyright (c) " + strconv.Itoa(time.Now().Year()) + " Jane Doe"
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("expected LICENSE to contain %q, got %q", expected, string(content))
+		}
+	})
+
+	t.Run("unknown-license", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		if err := writeLicense("wtfpl", "Jane Doe"); err == nil {
+			t.Error("expected an error for an unknown license, got nil")
+		}
+	})
+}
+
+func TestDetectLicense(t *testing.T) {
+	testCases := []struct {
+		file     string
+		content  string
+		expected string
+	}{
+		{"LICENSE", "", ""},
+		{"LICENSE.md", "Some custom license text", ""},
+		{"COPYING", "// SPDX-License-Identifier: GPL-3.0-or-later\n", "GPL-3.0-or-later"},
+		{"LICENSE.txt", "Copyright 2020\n\nPermission is hereby granted, free of charge, to any person", "MIT"},
+		{"LICENSE", "Redistribution and use in source and binary forms, with or without\nNeither the name of", "BSD-3-Clause"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected+tc.file, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			originalDir, _ := os.Getwd()
+			os.Chdir(tmpDir)
+			defer os.Chdir(originalDir)
+
+			if tc.content != "" {
+				os.WriteFile(tc.file, []byte(tc.content), 0644)
+			}
+
+			if got := detectLicense(); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAddLicense(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	originalPath := os.Getenv("PATH")
+	defer os.Setenv("PATH", originalPath)
+
+	t.Run("author-from-git", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		tmpBinDir := t.TempDir()
+		createMockGit(t, tmpBinDir, "Git Author", 0)
+		t.Setenv("PATH", tmpBinDir)
+
+		if err := addLicense("mit", "", false); err != nil {
+			t.Fatalf("addLicense() failed: %v", err)
+		}
+		content, _ := os.ReadFile("LICENSE")
+		if !strings.Contains(string(content), "Git Author") {
+			t.Errorf("expected LICENSE to name the git author, got %q", string(content))
+		}
+	})
+
+	t.Run("author-from-module", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		tmpBinDir := t.TempDir()
+		createMockGit(t, tmpBinDir, "", 1)
+		t.Setenv("PATH", tmpBinDir)
+
+		os.WriteFile("go.mod", []byte("module github.com/moduleuser/project"), 0644)
+
+		if err := addLicense("bsd-3-clause", "", false); err != nil {
+			t.Fatalf("addLicense() failed: %v", err)
+		}
+		content, _ := os.ReadFile("LICENSE")
+		if !strings.Contains(string(content), "moduleuser") {
+			t.Errorf("expected LICENSE to name the module owner, got %q", string(content))
+		}
+	})
+
+	t.Run("refuses-to-overwrite", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		os.WriteFile("LICENSE", []byte("existing"), 0644)
+
+		if err := addLicense("mit", "Jane Doe", false); err == nil {
+			t.Error("expected an error when LICENSE exists, got nil")
+		}
+		if err := addLicense("mit", "Jane Doe", true); err != nil {
+			t.Fatalf("addLicense() with force failed: %v", err)
+		}
+		if got := detectLicense(); got != "MIT" {
+			t.Errorf("expected the license to be overwritten with MIT, got %q", got)
+		}
+	})
+
+	t.Run("apache-author", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		if err := addLicense("apache-2.0", "Jane Doe", false); err != nil {
+			t.Fatalf("addLicense() failed: %v", err)
+		}
+		content, _ := os.ReadFile("LICENSE")
+		year := strconv.Itoa(time.Now().Year())
+		if !strings.Contains(string(content), "Copyright "+year+" Jane Doe") {
+			t.Errorf("expected the appendix to name the year and author, got %q", string(content))
+		}
+		if strings.Contains(string(content), "[yyyy]") {
+			t.Error("expected the placeholder copyright line to be replaced")
+		}
+	})
+
+	t.Run("no-copyright-line", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		// no git and no go.mod, the author is not needed anyway
+		t.Setenv("PATH", t.TempDir())
+
+		buff.Reset()
+		if err := addLicense("unlicense", "Jane Doe", false); err != nil {
+			t.Fatalf("addLicense() failed: %v", err)
+		}
+		content, _ := os.ReadFile("LICENSE")
+		if strings.Contains(string(content), "Jane Doe") {
+			t.Errorf("expected the Unlicense not to name the author, got %q", string(content))
+		}
+		if !strings.Contains(buff.String(), "the author is not used") {
+			t.Errorf("expected a note that the author is not used, got %q", buff.String())
+		}
+	})
+}
+
+func TestGenerateScoopFileLicense(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+	color.NoColor = true
+
+	origStdout := os.Stdout
+	origStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	os.Stderr = w
+	defer func() {
+		os.Stdout = origStdout
+		os.Stderr = origStderr
+	}()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	os.Mkdir("dist", 0755)
+	os.WriteFile("go.mod", []byte("module github.com/testuser/tool"), 0644)
+	os.WriteFile("main.go", []byte("package main\nconst version = \"1.0.0\""), 0644)
+	os.WriteFile(filepath.Join("dist", "tool_1.0.0_checksums.txt"), []byte("abc  tool_1.0.0_Windows_x86_64.zip"), 0644)
+
+	if err := writeLicense("apache-2.0", "Jane Doe"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := generateScoopFile(ScoopOptions{}); err != nil {
+		t.Fatalf("generateScoopFile failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join("dist", "tool.json"))
+	expected := fmt.Sprintf(`"license": %q`, "Apache-2.0")
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("expected manifest to contain %q, got %s", expected, content)
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"os"
     "bytes"
 	"os/exec"
@@ -12,6 +14,7 @@ import (
 	"path/filepath"
 
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 	cp "github.com/otiai10/copy"
 )
 
@@ -22,6 +25,7 @@ type Info struct {
 	name			string
 	project			string
 	version			string
+	version_pos		string
 	git_tag 		string
 	git_tag_commit	string
 	git_head		string
@@ -30,6 +34,54 @@ type Info struct {
 	gh_username		string	
 	gh_uri			string
 	gh_origin		string
+	gh_homepage		string
+	forge			Forge
+}
+
+// struct for capturing the init subcommand options
+type InitOptions struct {
+	template		string
+	username		string
+	host			string
+	origin_style	string
+	branch			string
+	no_git			bool
+	no_goreleaser	bool
+	yes				bool
+	license			string
+}
+
+// struct for capturing the bump subcommand options
+type BumpOptions struct {
+	arg				string	// the version for set, or the level a new prerelease starts from
+	metadata		string
+	commit			bool
+	tag				bool
+	sign			bool
+	message			string
+	allow_dirty		bool
+}
+
+// struct for capturing the release subcommand options
+type ReleaseOptions struct {
+	check			bool
+	snapshot		bool
+}
+
+// struct for capturing the scoop subcommand options
+type ScoopOptions struct {
+	bucket			string
+}
+
+// struct for capturing the build subcommand options
+type BuildOptions struct {
+	release			bool
+}
+
+// data made available to the bump commit and tag message templates
+type BumpData struct {
+	Version			string
+	Previous		string
 }
 
 
@@ -73,13 +125,100 @@ func run() (string, error) {
 	case "init":
 		banner()
 
-		if len(os.Args) < 3 {
+		var opts InitOptions
+		var list_templates bool
+		fs := flag.NewFlagSet("init", flag.ContinueOnError)
+		fs.StringVar(&opts.template, "template", "", "name of the project template")
+		fs.BoolVar(&list_templates, "list-templates", false, "list the available templates and exit")
+		fs.StringVar(&opts.username, "username", "", "username on the git host")
+		fs.StringVar(&opts.host, "host", "", "git host of the repository")
+		fs.StringVar(&opts.origin_style, "origin-style", "", "format of the git origin: ssh or https")
+		fs.StringVar(&opts.branch, "branch", "", "name of the initial git branch")
+		fs.BoolVar(&opts.no_git, "no-git", false, "do not initialize a git repository")
+		fs.BoolVar(&opts.no_goreleaser, "no-goreleaser", false, "do not set up goreleaser")
+		fs.BoolVar(&opts.yes, "yes", false, "never ask for confirmation")
+		fs.StringVar(&opts.license, "license", "", "license to add to the project")
+
+		args, ef := parseFlags(fs, os.Args[2:])
+		if ef != nil {
+			color.Red("❌  Invalid flags for init subcommand.")
+			printUsage()
+			return "invalid flags for init", ef
+		}
+
+		if list_templates {
+			err = printTemplates()
+			break
+		}
+
+		if len(args) < 1 {
 			color.Red("❌  Missing argument for init subcommand.")
 			printUsage()
 			return "missing argument for init", fmt.Errorf("missing argument for init")
 		}
 
-		err = createProject(os.Args[2])
+		err = createProject(args[0], opts)
+
+	// add a license to the project
+	case "license":
+		banner()
+
+		var author string
+		var force bool
+		fs := flag.NewFlagSet("license", flag.ContinueOnError)
+		fs.StringVar(&author, "author", "", "copyright holder named in the license")
+		fs.BoolVar(&force, "force", false, "overwrite an existing license file")
+
+		args, ef := parseFlags(fs, os.Args[2:])
+		if ef != nil {
+			color.Red("❌  Invalid flags for license subcommand.")
+			printUsage()
+			return "invalid flags for license", ef
+		}
+
+		if len(args) < 1 {
+			color.Red("❌  Missing argument for license subcommand. Use one of: " + strings.Join(getLicenseIds(), ", "))
+			printUsage()
+			return "missing argument for license", fmt.Errorf("missing argument for license")
+		}
+
+		err = addLicense(args[0], author, force)
+
+	// read and write the gopher settings
+	case "config":
+		banner()
+
+		var project bool
+		fs := flag.NewFlagSet("config", flag.ContinueOnError)
+		fs.BoolVar(&project, "project", false, "use the .gopher.toml file in the current directory")
+
+		args, ef := parseFlags(fs, os.Args[2:])
+		if ef != nil {
+			color.Red("❌  Invalid flags for config subcommand.")
+			printUsage()
+			return "invalid flags for config", ef
+		}
+
+		if len(args) < 1 {
+			color.Red("❌  Missing argument for config subcommand. Use get, set, list or edit.")
+			printUsage()
+			return "missing argument for config", fmt.Errorf("missing argument for config")
+		}
+
+		switch {
+		case args[0] == "list":
+			err = configList()
+		case args[0] == "edit":
+			err = configEdit(project)
+		case args[0] == "get" && len(args) == 2:
+			err = configGet(args[1])
+		case args[0] == "set" && len(args) == 3:
+			err = configSet(args[1], args[2], project)
+		default:
+			color.Red("❌  Invalid arguments for config subcommand.")
+			printUsage()
+			return "invalid arguments for config", fmt.Errorf("invalid arguments for config")
+		}
 
 	// create a Makefile for the project
 	case "make":
@@ -94,7 +233,26 @@ func run() (string, error) {
 	// build the project and zip it
 	case "release":
 		banner()
-		err = release()
+
+		var opts ReleaseOptions
+		fs := flag.NewFlagSet("release", flag.ContinueOnError)
+		fs.BoolVar(&opts.check, "check", false, "only run the pre-flight checks")
+		fs.BoolVar(&opts.snapshot, "snapshot", false, "build the release artifacts locally without tagging or publishing")
+
+		_, ef := parseFlags(fs, os.Args[2:])
+		if ef != nil {
+			color.Red("❌  Invalid flags for release subcommand.")
+			printUsage()
+			return "invalid flags for release", ef
+		}
+
+		if opts.check && opts.snapshot {
+			color.Red("❌  The --check and --snapshot flags can't be used together.")
+			printUsage()
+			return "invalid flags for release", fmt.Errorf("--check and --snapshot can't be used together")
+		}
+
+		err = release(opts)
 
 	case "info":
 		banner()
@@ -104,26 +262,129 @@ func run() (string, error) {
 			displayInfo(i)
 		}
 
+	// write the changelog section for the current version
+	case "changelog":
+		banner()
+
+		var unreleased bool
+		fs := flag.NewFlagSet("changelog", flag.ContinueOnError)
+		fs.BoolVar(&unreleased, "unreleased", false, "print the unreleased changes instead of writing CHANGELOG.md")
+
+		_, ef := parseFlags(fs, os.Args[2:])
+		if ef != nil {
+			color.Red("❌  Invalid flags for changelog subcommand.")
+			printUsage()
+			return "invalid flags for changelog", ef
+		}
+
+		err = generateChangelog(unreleased)
+
 	// generate a scoop manifest file
 	case "scoop":
 		banner()
-		err = generateScoopFile()
+
+		var opts ScoopOptions
+		fs := flag.NewFlagSet("scoop", flag.ContinueOnError)
+		fs.StringVar(&opts.bucket, "bucket", "", "local clone of a scoop bucket to publish the manifest to")
+
+		_, ef := parseFlags(fs, os.Args[2:])
+		if ef != nil {
+			color.Red("❌  Invalid flags for scoop subcommand.")
+			printUsage()
+			return "invalid flags for scoop", ef
+		}
+
+		err = generateScoopFile(opts)
+
+	// generate a Homebrew formula
+	case "brew":
+		banner()
+		err = generateBrewFormula()
+
+	// generate the winget manifests
+	case "winget":
+		banner()
+		err = generateWingetManifests()
+
+	// generate an AUR package
+	case "aur":
+		banner()
+		err = generateAurPackage()
+
+	// wrap the linux binaries into deb or rpm packages
+	case "package":
+		banner()
+
+		if len(os.Args) < 3 {
+			color.Red("❌  Missing argument for package subcommand. Use one of: " + strings.Join(getPackageFormatNames(), ", "))
+			printUsage()
+			return "missing argument for package", fmt.Errorf("missing argument for package")
+		}
+
+		err = packageProject(os.Args[2])
+
+	// generate a nix flake
+	case "nix":
+		banner()
+		err = generateFlake()
 
 	case "install":
 		banner()
 		err = installProject()
 
+	// check the release archives against the checksums file
+	case "verify":
+		banner()
+		err = verify()
+
+	// cross compile the project without goreleaser
+	case "build":
+		banner()
+
+		var opts BuildOptions
+		fs := flag.NewFlagSet("build", flag.ContinueOnError)
+		fs.BoolVar(&opts.release, "release", false, "also create the release archives and the checksums file")
+
+		_, ef := parseFlags(fs, os.Args[2:])
+		if ef != nil {
+			color.Red("❌  Invalid flags for build subcommand.")
+			printUsage()
+			return "invalid flags for build", ef
+		}
+
+		err = build(opts)
+
     // bump version number
     case "bump":
         banner()
 
-        if len(os.Args) < 3 {
+        var opts BumpOptions
+        fs := flag.NewFlagSet("bump", flag.ContinueOnError)
+        fs.StringVar(&opts.metadata, "metadata", "", "build metadata appended to the new version after a +")
+        fs.BoolVar(&opts.commit, "commit", false, "commit the version change")
+        fs.BoolVar(&opts.tag, "tag", false, "commit the version change and tag it with an annotated tag")
+        fs.BoolVar(&opts.sign, "sign", false, "commit the version change and tag it with a signed tag")
+        fs.StringVar(&opts.message, "message", "", "commit message template, e.g. chore: release v{{.Version}}")
+        fs.BoolVar(&opts.allow_dirty, "allow-dirty", false, "bump even if the git working tree has uncommitted changes")
+
+        args, ef := parseFlags(fs, os.Args[2:])
+        if ef != nil {
+            color.Red("❌  Invalid flags for bump subcommand.")
+            printUsage()
+            return "invalid flags for bump", ef
+        }
+
+        if len(args) < 1 {
             color.Red("❌  Missing argument for bump subcommand. Use minor, major, or patch.")
             printUsage()
             return "missing argument for bump", fmt.Errorf("missing argument for bump")
         }
 
-        err = versionBump(os.Args[2])
+        if len(args) > 1 {
+            opts.arg = args[1]
+        }
+
+        err = versionBump(args[0], opts)
 
 	// print usage and exit
 	default:
@@ -145,9 +406,34 @@ func printUsage() {
 	fmt.Println("\nUsage: gopher [subcommand] <arguments>")
 	fmt.Println("\nSubcommands:")
 	fmt.Println("")
-	fmt.Println("  init <string>")
+	fmt.Println("  init <string> [flags]")
 	fmt.Println("        bootstrap a new project with where the <string> is the project name")
 	fmt.Println("        in the format username/projectname or a full github uri like github.com/username/projectname")
+	fmt.Println("        --template <name>       render the project from ~/.config/gopher/templates/<name>/ or a built-in template")
+	fmt.Println("        --username <name>       username on the git host (defaults to the username setting)")
+	fmt.Println("        --host <host>           git host of the repository (default github.com)")
+	fmt.Println("        --origin-style <style>  git origin format: ssh or https (default ssh)")
+	fmt.Println("        --branch <name>         name of the initial git branch (default main)")
+	fmt.Println("        --no-git                do not initialize a git repository")
+	fmt.Println("        --no-goreleaser         do not set up goreleaser")
+	fmt.Println("        --yes                   never ask for confirmation")
+	fmt.Println("        --license <id>          add a LICENSE file: mit, apache-2.0, bsd-3-clause, mpl-2.0 or unlicense")
+	fmt.Println("")
+	fmt.Println("  init --list-templates")
+	fmt.Println("        list the available project templates with their descriptions")
+	fmt.Println("")
+	fmt.Println("  license <id> [--author <name>] [--force]")
+	fmt.Println("        add a LICENSE file to the project, the <id> can be")
+	fmt.Println("        mit, apache-2.0, bsd-3-clause, mpl-2.0 or unlicense")
+	fmt.Println("")
+	fmt.Println("  config list")
+	fmt.Println("        print every setting with its value and where the value comes from")
+	fmt.Println("  config get <key>")
+	fmt.Println("        print the value of a single setting")
+	fmt.Println("  config set <key> <value> [--project]")
+	fmt.Println("        save a setting in ~/.config/gopher/config.toml or with --project in .gopher.toml")
+	fmt.Println("  config edit [--project]")
+	fmt.Println("        open the global or project config file in $EDITOR")
 	fmt.Println("")
 	fmt.Println("  info")
 	fmt.Println("        print project information known to gopher")
@@ -158,19 +444,64 @@ func printUsage() {
 	fmt.Println("  just")
 	fmt.Println("        create a simple Justfile for the project")
 	fmt.Println("")
-	fmt.Println("  release")
+	fmt.Println("  release [--check] [--snapshot]")
 	fmt.Println("        build and release the project using goreleaser")
+	fmt.Println("        the pre-flight checks in the release.checks setting must pass before the tag is made")
+	fmt.Println("        --check only runs the checks")
+	fmt.Println("        --snapshot builds the artifacts in dist/ without tagging or publishing anything")
 	fmt.Println("")
-	fmt.Println("  scoop")
+	fmt.Println("  changelog [--unreleased]")
+	fmt.Println("        add a section for the current version to CHANGELOG.md from the conventional commits")
+	fmt.Println("        since the last tag, --unreleased only prints the changes without writing the file")
+	fmt.Println("")
+	fmt.Println("  scoop [--bucket <path>]")
 	fmt.Println("        generate a Scoop manifest file for the project")
+	fmt.Println("        --bucket copies it into a local clone of a scoop bucket and commits it,")
+	fmt.Println("        the scoop.bucket setting sets the default path")
+	fmt.Println("")
+	fmt.Println("  brew")
+	fmt.Println("        generate a Homebrew formula for the macOS and Linux archives in dist/")
+	fmt.Println("")
+	fmt.Println("  winget")
+	fmt.Println("        generate the winget manifests for the Windows archives in dist/")
+	fmt.Println("")
+	fmt.Println("  aur")
+	fmt.Println("        generate a -bin PKGBUILD and .SRCINFO for the Linux archives in dist/")
+	fmt.Println("")
+	fmt.Println("  package <format>")
+	fmt.Println("        wrap the Linux binaries in dist/ into packages; the <format> can")
+	fmt.Println("        be one of: deb, rpm")
+	fmt.Println("")
+	fmt.Println("  nix")
+	fmt.Println("        generate a flake.nix that builds the project with buildGoModule")
 	fmt.Println("")
 	fmt.Println("  install")
 	fmt.Println("        install the project binary in the user's private bin directory")
 	fmt.Println("        typically ~/bin")
 	fmt.Println("")
-    fmt.Println("  bump <string>")
+	fmt.Println("  build [--release]")
+	fmt.Println("        cross compile the project with go build, without goreleaser")
+	fmt.Println("        the targets come from the build.targets setting or .goreleaser.yaml")
+	fmt.Println("        --release also creates the archives and the checksums file in dist/")
+	fmt.Println("")
+	fmt.Println("  verify")
+	fmt.Println("        check the archives in dist/ against the checksums file and make sure")
+	fmt.Println("        each one contains the binary for its os")
+	fmt.Println("")
+    fmt.Println("  bump <string> [--metadata <string>] [--commit] [--tag] [--sign] [--message <template>] [--allow-dirty]")
     fmt.Println("        bump the version number in the main file")
     fmt.Println("        the <string> can be major, minor, or build / patch")
+    fmt.Println("        pre increments the current prerelease, e.g. 1.2.3-rc.1 to 1.2.3-rc.2")
+    fmt.Println("        rc, beta or alpha start or advance that prerelease, add major or minor to choose the base")
+    fmt.Println("        release drops the prerelease suffix, e.g. 1.2.3-rc.2 to 1.2.3")
+    fmt.Println("        --metadata adds build metadata, e.g. 1.2.3+exp.sha.5114f85")
+    fmt.Println("        --commit commits the bumped files, --tag also creates an annotated v<version> tag")
+    fmt.Println("        --sign makes it a signed tag and implies --tag, --message sets the commit message template")
+    fmt.Println("        bump refuses to run on a dirty git tree unless --allow-dirty is given")
+    fmt.Println("  bump set <version>")
+    fmt.Println("        set the version number, it must be greater than the current one")
+    fmt.Println("  bump auto")
+    fmt.Println("        pick major, minor or patch from the conventional commits since the last tag")
 	fmt.Println("")
 	fmt.Println("  version")
 	fmt.Println("        display version number of the gopher tool and exit")
@@ -179,32 +510,82 @@ func printUsage() {
 	fmt.Println("        display this help message and exit")
 }
 
+// check that go, git and goreleaser are all installed
 func check() error {
-	// check if go is installed
-	_, err := exec.LookPath("go")
-	if err != nil {
+	return checkTools("go", "git", "goreleaser")
+}
+
+// check that the given command line tools are installed
+func checkTools(tools ...string) error {
+
+	names := map[string]string{
+		"go":         "Go",
+		"git":        "Git",
+		"goreleaser": "Goreleaser",
+	}
+
+	for _, tool := range tools {
+		_, err := exec.LookPath(tool)
+		if err != nil {
+			name, ok := names[tool]
+			if !ok {
+				name = tool
+			}
+			fmt.Print("💥 ")
+			color.Red(name + " is not installed. Please install " + name + " and try again.")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reports whether gopher can ask the user questions on stdin
+var isInteractive = func() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// ask the user for a line of input, fails instead of blocking when stdin is not a terminal
+func prompt(question string) (string, error) {
+
+	if !isInteractive() {
 		fmt.Print("💥 ")
-		color.Red("Go is not installed. Please install Go and try again.")
-		return err
+		color.Red("Input required but stdin is not a terminal: " + question)
+		return "", fmt.Errorf("input required but stdin is not a terminal")
 	}
 
-	// check if git is installed
-	_, err = exec.LookPath("git")
-	if err != nil {
+	color.Red("🛑 STOP: INPUT REQUIRED")
+	fmt.Print("❓ " + question)
+
+	var answer string
+	fmt.Scanln(&answer)
+	answer = strings.TrimSpace(answer)
+
+	if answer == "" {
 		fmt.Print("💥 ")
-		color.Red("Git is not installed. Please install Git and try again.")
-		return err
+		color.Red("No input given.")
+		return "", fmt.Errorf("no input given")
 	}
+	return answer, nil
+}
 
-	// check if goreleaser is installed
-	_, err = exec.LookPath("goreleaser")
-	if err != nil {
+// ask the user a yes or no question, anything but y or yes counts as no
+func confirm(question string) (bool, error) {
+
+	if !isInteractive() {
 		fmt.Print("💥 ")
-		color.Red("Goreleaser is not installed. Please install Goreleaser and try again.")
-		return err
+		color.Red("Confirmation required but stdin is not a terminal: " + question)
+		return false, fmt.Errorf("confirmation required but stdin is not a terminal")
 	}
-	
-	return nil
+
+	fmt.Print("❓ " + question + " [y/N]: ")
+
+	var answer string
+	fmt.Scanln(&answer)
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes", nil
 }
 
 // find a text line inside a file that matches the pattern
@@ -242,10 +623,71 @@ func replaceInFile(filename string, find string, replace string) error {
 	return nil
 }
 
+// parse the flags of a subcommand allowing them to be mixed with positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+
+	fs.SetOutput(io.Discard)
+
+	var positional []string
+	for {
+		err := fs.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	return positional, nil
+}
+
 // This function creates a new project with a given name.
-func createProject(uri string) error {
+func createProject(uri string, opts InitOptions) error {
+
+	// options that were not set come from the config files or the defaults
+	opts.template = getSettingValue("init.template", opts.template)
+	opts.host = getSettingValue("init.host", opts.host)
+	opts.origin_style = getSettingValue("init.origin_style", opts.origin_style)
+	opts.branch = getSettingValue("init.branch", opts.branch)
+	opts.license = getSettingValue("init.license", opts.license)
+
+	if opts.origin_style != "ssh" && opts.origin_style != "https" {
+		fmt.Print("💥 ")
+		color.Red("Invalid origin style " + opts.origin_style + ". Use ssh or https.")
+		return fmt.Errorf("invalid origin style: %s", opts.origin_style)
+	}
+
+	if _, ok := licenses[strings.ToLower(opts.license)]; opts.license != "" && !ok {
+		fmt.Print("💥 ")
+		color.Red("Unknown license " + opts.license + ". Use one of: " + strings.Join(getLicenseIds(), ", "))
+		return fmt.Errorf("unknown license: %s", opts.license)
+	}
+
+	color.Cyan("Looking up the " + opts.template + " template...")
+	tmpl, err := findTemplate(opts.template)
+	if err != nil { return err }
+
+	meta, err := loadTemplateMeta(tmpl)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return err
+	}
 
-	err := check()
+	use_goreleaser := !opts.no_goreleaser && !meta.NoGoreleaser
+
+	// only require the tools we are actually going to use
+	tools := []string{"go"}
+	if !opts.no_git {
+		tools = append(tools, "git")
+	}
+	if use_goreleaser {
+		tools = append(tools, "goreleaser")
+	}
+	err = checkTools(tools...)
 	if err != nil {	return err }
 
 	errors := 0
@@ -253,45 +695,77 @@ func createProject(uri string) error {
 
 	// check if we got a name or a uri
 	if strings.Contains(uri, "/") {
-		color.Cyan("Detected a github uri, extracting the name...")
-		name = getName(uri)
-		username = getUsername(uri)
-		if !strings.Contains(uri, "github.com") {
-			uri = "github.com/" + uri
+		color.Cyan("Detected a repository uri, extracting the name...")
+		var host string
+		host, username, name = parseModule(uri)
+		if host != "" {
+			opts.host = host
 		}
 	} else {
-		color.Yellow("⚠  project name is not a github URI")
-		color.Cyan("Checking if GOPHER_USERNAME environment variable is set...")
-
-		// get the value of GOPHER_USERNAME environment variable
-		gh_username := os.Getenv("GOPHER_USERNAME")
-
-		if gh_username == "" {
-			color.Yellow("⚠  GOPHER_USERNAME environment variable is not set.")
-			color.Red("🛑 STOP: INPUT REQUIRED")
-			fmt.Print("❓Enter your github username and press [ENTER]: ")
-			fmt.Scanln(&gh_username)
-			color.White("💬 Don't want to be asked again? Use a github uri when initializing the project.")
-			color.White("💬 Example: gopher init github.com/username/project")
-			color.White("💬 Alternatively set the GOPHER_USERNAME environment variable.")
+		color.Yellow("⚠  project name is not a repository URI")
+		name = uri
+
+		color.Cyan("Looking up the username setting...")
+		username = getSettingValue("username", opts.username)
+
+		if username == "" {
+			color.Yellow("⚠  username is not set in GOPHER_USERNAME or the gopher config.")
+			username, err = prompt("Enter your " + opts.host + " username and press [ENTER]: ")
+			if err != nil {
+				color.White("💬 Use a full uri like gopher init " + opts.host + "/username/" + name)
+				color.White("💬 or pass --username, or run gopher config set username <name>.")
+				return err
+			}
+			color.White("💬 Don't want to be asked again? Use a full uri when initializing the project.")
+			color.White("💬 Example: gopher init " + opts.host + "/username/project")
+			color.White("💬 Alternatively run gopher config set username <name>.")
 		}
 
-		color.Blue("🆗 Got your github username: " + gh_username)
-		name = uri
-		username = gh_username
-		uri = "github.com/" + gh_username + "/" + name
+		color.Blue("🆗 Got your username: " + username)
 	}
 
-	gh_origin := "git@github.com:" + username + "/" + name + ".git"
+	forge := getForge(opts.host)
+	uri = forge.module(username, name)
+
+	gh_origin := forge.sshOrigin(username, name)
+	if opts.origin_style == "https" {
+		gh_origin = forge.httpsOrigin(username, name)
+	}
+
+	data := TemplateData{
+		Name:     name,
+		Package:  getPackageName(name),
+		Username: username,
+		URI:      uri,
+		Origin:   gh_origin,
+	}
 
 	fmt.Println()
 	color.White("📝 Project information:")
 	color.White("  Project Name:\t" + name)
-	color.White("  Github user: \t" + username)
-	color.White("  Github URI: \t" + uri)
-	color.White("  Github repo: \t" + gh_origin)
+	color.White("  Template:\t" + opts.template)
+	color.White("  " + forge.title + " user: \t" + username)
+	color.White("  " + forge.title + " URI: \t" + uri)
+	if !opts.no_git {
+		color.White("  " + forge.title + " repo: \t" + gh_origin)
+		color.White("  Git branch: \t" + opts.branch)
+	}
 	fmt.Println()
 
+	// refuse to silently write into an existing non-empty directory
+	if entries, err := os.ReadDir(name); err == nil && len(entries) > 0 && !opts.yes {
+		color.Yellow("⚠  Directory " + name + " already exists and is not empty.")
+		ok, err := confirm("Create the project in it anyway?")
+		if err != nil {
+			color.White("💬 Pass --yes to write into an existing directory without asking.")
+			return err
+		}
+		if !ok {
+			color.Red("❌  Aborted.")
+			return fmt.Errorf("directory %s already exists", name)
+		}
+	}
+
 	// create a new directory
 	color.Cyan("Creating project " + name + "...")
 	os.Mkdir(name, 0755)
@@ -312,120 +786,117 @@ func createProject(uri string) error {
 
 	color.Blue("🆗 go module initiated.")
 
-	// create .gitignore file
-	color.Cyan("Creating .gitignore file...")
-	gfile, err := os.Create(".gitignore")
+	// render the project template
+	color.Cyan("Rendering the " + opts.template + " template...")
+	err = renderTemplate(tmpl, data)
 	if err != nil {
 		fmt.Print("💥 ")
-		color.Red("Error creating .gitignore", err)
-		os.Exit(1)
+		color.Red(err.Error())
+		errors++
+	} else {
+		color.Blue("🆗 project files created.")
 	}
-	defer gfile.Close()
-
-	gfile.WriteString(".env\n")
-	gfile.WriteString(name + "\n")
-	gfile.WriteString(name + "*.exe\n")
-	gfile.WriteString(name + ".zip\n")
-	gfile.WriteString(name + ".tgz\n")
-	gfile.WriteString(name + "_*.zip\n")
-	gfile.WriteString(name + "_*.tgz\n")
 
-	color.Blue("🆗 .gitignore file created.")
+	// add the license, the author comes from git config if available
+	if opts.license != "" {
+		author := getGitAuthor()
+		if author == "" {
+			author = username
+		}
+		if writeLicense(opts.license, author) != nil {
+			errors++
+		}
+	}
 
-	// create README.md file
-	color.Cyan("Creating README.md file...")
-	rfile, err := os.Create("README.md")
-	if err != nil {
-		fmt.Print("💥 ")
-		color.Red("Error creating README.md file")
-		color.Red(err.Error())
-		return err
+	if opts.no_git {
+		color.White("💬  Skipping git repository setup.")
+	} else if setupGit(opts.branch, gh_origin) != nil {
+		errors++
 	}
-	defer rfile.Close()
-	rfile.WriteString("# " + name + "\n")
 
-	color.Blue("🆗 README file created.")
+	// set up goreleaser unless the template or the user opts out of it
+	if !use_goreleaser {
+		color.White("💬  Skipping goreleaser init.")
+	} else if setupGoreleaser() != nil {
+		errors++
+	}
 
-	// create a simple main.go file
-	createMainFile()
+	// print the success message
+	if errors == 0 {
+		color.Green("✔  Project " + name + " created successfully.")
+		return nil
+	} else {
+		color.Green("⚠  Project " + name + " created with some errors.")
+		return fmt.Errorf("project creation completed with %d errors", errors)
+	}
+}
 
-	// create a simple test file
-	createTestFile()
+// initialize the git repository and add the origin remote
+func setupGit(branch string, origin string) error {
 
-	// run the git init command with -b main
-	color.Cyan("Running git init -b main...")
-	cmd = exec.Command("git", "init", "-b", "main")
+	// run the git init command with -b branch
+	color.Cyan("Running git init -b " + branch + "...")
+	cmd := exec.Command("git", "init", "-b", branch)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	e = cmd.Run()
+	err := cmd.Run()
 
-	if e != nil {
+	if err != nil {
 		fmt.Print("💥 ")
-		color.Red(e.Error())
-		errors++
+		color.Red(err.Error())
+		return err
 	}
 
 	color.Blue("🆗 git repository initiated.")
 
-	// add github as origin
+	// add the remote as origin
 	color.Cyan("Running git remote add origin...")
-	cmd = exec.Command("git", "remote", "add", "origin", gh_origin)
+	cmd = exec.Command("git", "remote", "add", "origin", origin)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	e = cmd.Run()
+	err = cmd.Run()
 
-	if e != nil {
+	if err != nil {
 		fmt.Print("💥 ")
-		color.Red(e.Error())
-		errors++
+		color.Red(err.Error())
+		return err
 	}
 
 	color.Blue("🆗 new origin repository added.")
-	color.White("💬  You can run git push -u origin main to push your project to github.")
+	color.White("💬  You can run git push -u origin " + branch + " to push your project.")
+	return nil
+}
+
+// run goreleaser init and adjust the generated config to gopher defaults
+func setupGoreleaser() error {
 
 	// run goreleaser init
 	color.Cyan("Running goreleaser init...")
-	cmd = exec.Command("goreleaser", "init")
+	cmd := exec.Command("goreleaser", "init")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	e = cmd.Run()
-	if e != nil {
+	err := cmd.Run()
+	if err != nil {
 		fmt.Print("💥 ")
-		color.Red(e.Error())
-		errors++
+		color.Red(err.Error())
+		return err
 	}
 	color.Blue("🆗 goreleaser configuration created.")
 	color.White("💬  You can edit the .goreleaser.yml file to customize the release process.")
 
-	yml_err := 0
-
-
 	// modify the goreleaser.yaml and replace {{ .ProjectName }}_ with {{ .ProjectName }}_{{ .Version }}_
-
 	color.Cyan("Modifying the .goreleaser.yaml file to include version in the archive names...")
 
 	err = replaceInFile(".goreleaser.yaml", "{{ .ProjectName }}_", "{{ .ProjectName }}_{{ .Version }}_")
-
 	if err != nil {
 		fmt.Print("💥 ")
 		color.Red("Error modifying .goreleaser.yaml file")
 		color.Red(err.Error())
-		errors++
-		yml_err++
-	}
-	
-	if yml_err == 0 {
-		color.Blue("🆗 .goreleaser.yaml file modified successfully.")
+		return err
 	}
 
-	// print the success message
-	if errors == 0 {
-		color.Green("✔  Project " + name + " created successfully.")
-		return nil
-	} else {
-		color.Green("⚠  Project " + name + " created with some errors.")
-		return fmt.Errorf("project creation completed with %d errors", errors)
-	}
+	color.Blue("🆗 .goreleaser.yaml file modified successfully.")
+	return nil
 }
 
 // get the main file name
@@ -458,13 +929,20 @@ func getInfo() (Info, error) {
 	if err != nil { return Info{}, err }
 
 
-	info.version, err = getVersion(info.name + ".go")
+	loc, err := getVersionLocation(info.name + ".go")
 	if err != nil { return Info{},err }
+	info.version = loc.value
+	info.version_pos = loc.String()
 
 	info.gh_uri, err = getModule()
 	if err != nil { return Info{}, err }
 
-	info.gh_username = getUsername(info.gh_uri)
+	host, owner, name := parseModule(info.gh_uri)
+	info.forge = getForge(host)
+	info.gh_username = owner
+	if owner != "" {
+		info.gh_homepage = info.forge.homepage(owner, name)
+	}
 	info.gh_origin, err = getGitOrigin()
 	if err != nil { return Info{}, err }
 
@@ -499,14 +977,18 @@ func displayInfo(info Info) {
 	fmt.Println()
 	color.White("📝 Project information:")
 	color.White("  Project Name:\t" + info.project)
-	color.White("  Version:\t" + info.version)
+	color.White("  Version:\t" + info.version + " (" + info.version_pos + ")")
 	color.White("  Git tag: \t" + info.git_tag + " (" + info.git_tag_commit + ")")
 	color.White("  Git HEAD: \t" + info.git_head)
 	color.White("  Git branch:\t" + branch)
 	color.White("  Git State: \t" + info.git_state)
-	color.White("  Github user: \t" + info.gh_username)
-	color.White("  Github URI: \t" + info.gh_uri)
-	color.White("  Github repo: \t" + info.gh_origin)
+	color.White("  Forge: \t" + info.forge.title + " (" + info.forge.host + ")")
+	color.White("  " + info.forge.title + " user: \t" + info.gh_username)
+	color.White("  " + info.forge.title + " URI: \t" + info.gh_uri)
+	color.White("  " + info.forge.title + " repo: \t" + info.gh_origin)
+	if info.gh_homepage != "" {
+		color.White("  Homepage: \t" + info.gh_homepage)
+	}
 	fmt.Println()
 
 
@@ -547,6 +1029,32 @@ func getGitClean() bool {
 	return true
 }
 
+// check if the current directory is inside a git working tree
+func getGitRepo() bool {
+	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(output)) == "true"
+}
+
+// list the tracked files with uncommitted changes, staged or not
+func getGitChanges() ([]string, error) {
+	cmd := exec.Command("git", "status", "--porcelain", "--untracked-files=no")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error reading the git status: %w", err)
+	}
+	var changes []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) != "" {
+			changes = append(changes, line)
+		}
+	}
+	return changes, nil
+}
+
 
 
 // get github origin from git
@@ -583,33 +1091,98 @@ func getGitCommit(tag string) string {
 }
 
 // release the project using goreleaser
-func release() error {
+func release(opts ReleaseOptions) error {
 
-	err := check()
+	// the checks don't need goreleaser
+	var err error
+	if opts.check {
+		err = checkTools("go", "git")
+	} else {
+		err = check()
+	}
 	if err != nil { return err }
 
-	color.Cyan("Releasing the project ...")
-	color.Cyan("This will build the project for multiple platforms and create a new github release.")
-	color.White("💬  Make sure you edit the .goreleaser.yml file to set up how the project should get released.")
-
 	name, en := getMainFileName()
 	if en != nil { return en }
 
-	version, ev := getVersion(name + ".go")
+	module, em := getModule()
+	if em != nil { return em }
+
+	host, owner, project := parseModule(module)
+	forge := getForge(host)
+
+	// a dry run of the pipeline, nothing gets tagged so the checks don't apply
+	if opts.snapshot {
+		return releaseSnapshot()
+	}
+
+	if !opts.check {
+		color.Cyan("Releasing the project ...")
+		color.Cyan("This will build the project for multiple platforms and create a new " + forge.title + " release.")
+		color.White("💬  Make sure you edit the .goreleaser.yml file to set up how the project should get released.")
+		color.White("💬  goreleaser reads the " + forge.title + " api token from the " + forge.tokenEnv() + " environment variable.")
+	}
+
+	loc, ev := getVersionLocation(name + ".go")
 	if ev != nil { return ev }
+	version := loc.value
+	tag := "v" + version
 
-	// add a tag for the current version
-	color.Cyan("Tagging the current version v" + version + "...")
-	cmd := exec.Command("git", "tag", "v"+version)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	e := cmd.Run()
-	if e != nil {
+	checks, ec := getReleaseChecks()
+	if ec != nil {
 		fmt.Print("💥 ")
-		color.Red(e.Error())
-		return e
+		color.Red(ec.Error())
+		return ec
+	}
+
+	ctx := ReleaseContext{version: version, tag: tag, source: loc.source}
+	if ep := runReleaseChecks(ctx, checks); ep != nil {
+		color.White("💬  Fix the problems above, or drop the check from the release.checks setting.")
+		return ep
+	}
+
+	if opts.check {
+		color.Green("✔  Ready to release " + tag)
+		return nil
+	}
+
+	var cmd *exec.Cmd
+	var e error
+
+	// only delete the tag on failure if we made it
+	created := false
+
+	if loc.source == versionSourceGit {
+		// the version is the tag, so there is nothing to add
+		if loc.file == "" {
+			fmt.Print("💥 ")
+			color.Red("There is no git tag to release. Create one with gopher bump.")
+			return fmt.Errorf("no git tag to release")
+		}
+		color.Blue("🆗 Releasing the existing git tag " + loc.file)
+	} else if exists, at_head := getTagState(tag); exists {
+		// bump --tag already tagged this commit
+		if !at_head {
+			fmt.Print("💥 ")
+			color.Red("The git tag " + tag + " already exists on another commit.")
+			return fmt.Errorf("git tag %s already exists", tag)
+		}
+		color.Blue("🆗 Releasing the existing git tag " + tag)
+	} else {
+		// add a tag for the current version
+		color.Cyan("Tagging the current version " + tag + "...")
+		cmd = exec.Command("git", "tag", tag)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		e = cmd.Run()
+		if e != nil {
+			fmt.Print("💥 ")
+			color.Red(e.Error())
+			return e
+		}
+		created = true
+		color.Blue("🆗 Git tag added successfully.")
 	}
-	color.Blue("🆗 Git tag added successfully.")
 
 	// run goreleaser release command
 	color.Cyan("Running goreleaser release...")
@@ -623,6 +1196,11 @@ func release() error {
 		fmt.Print("💥 ")
 		color.Red(egr.Error())
 
+		// the tag was there before us, leave it alone
+		if !created {
+			return egr
+		}
+
 		// delete the tag we just created
 		color.Cyan("Deleting the git tag v" + version + "...")
 		cmd = exec.Command("git", "tag", "-d", "v"+version)
@@ -640,177 +1218,49 @@ func release() error {
 	}
 
 	color.Blue("🆗 goreleaser ran successfully.")
-	color.Green("✔  Project released successfully. Check your github page for the new release")
-	return nil
-}
-
-
-// generate a scoop manifest file
-func generateScoopFile() error {
-
-	color.Cyan("Generating scoop manifest file...")
-
-	// check if the dist/ folder exists in the project directory and if not exit
-	if _, err := os.Stat("dist"); os.IsNotExist(err) {
-		// warn
-		color.Yellow("⚠  dist/ folder does not exist in the project directory.")
-		color.White("💬  Make sure you have built the project using gopher release")
-		return err
-	}
-
-	
-	var name, username, version, description, homepage, url string
-
-	color.Cyan("Getting module string from go.mod file...")
-	uri, em := getModule()
-	if em != nil { return em }
-
-	// check if the module string is a uri
-	if strings.Contains(uri, "/") {
-		name = getName(uri)
-		username = getUsername(uri)
+	if owner != "" {
+		color.Green("✔  Project released successfully. Check " + forge.releasesPage(owner, project) + " for the new release")
 	} else {
-		name = uri
-        // check if the username is in an environment variable
-        color.Cyan("Checking if GOPHER_USERNAME environment variable is set...")
-        username = os.Getenv("GOPHER_USERNAME")
-
-        if username == "" {
-            color.Yellow("⚠  GOPHER_USERNAME environment variable is not set.")
-            // ask user for github username since it's not in the module string
-            color.Red("🛑 STOP: INPUT REQUIRED")
-            fmt.Print("❓ Enter your github username and press [ENTER]: ")
-            fmt.Scanln(&username)
-        }
+		color.Green("✔  Project released successfully. Check your " + forge.title + " page for the new release")
 	}
+	return nil
+}
 
-    color.Blue("🆗 Got the project name: " + name)
-    color.Blue("🆗 Got your github username: " + username)
-
-	color.Cyan("Getting version from gopher.go file...")
-	mainfile, en := getMainFileName()
-	if en != nil { return en }
-
-	var ev error
-	version, ev = getVersion(mainfile + ".go")
-	if ev != nil { return ev }
-
-    color.Blue("🆗 Got the project version: " + version)
-
-	color.Cyan("Adding generic description, you can edit it later...")
-	description = "A new scoop package"
-
-	color.Cyan("Creating the homepage url...")
-	homepage = "https://github.com/" + username + "/" + name
-
-    color.Blue("🆗 Homepage url: " + homepage)
-
-	color.Cyan("Creating the download url...")
-	url = "https://github.com/" + username + "/" + name + "/releases/download/v" + version + "/" + name + "_" + version + "_Windows_x86_64.zip"
-
-    color.Blue("🆗 Download url: " + url)
-
-	color.Cyan("Creating the scoop manifest...")
-
-	// create the scoop manifest file
-	manifest := fmt.Sprintf(`{
-    "version": "%s",
-    "description": "%s",
-    "homepage": "%s",
-    "checkver": "github",
-    "url": "%s",
-    "bin": "%s",
-    "license": "freeware"
-}`, version, description, homepage, url, name+".exe")
 
-    color.Blue("🆗 Manifest created successfully.")
+// build the release artifacts locally without tagging or publishing anything
+func releaseSnapshot() error {
 
-	scoopfile_path := "dist" + string(os.PathSeparator) + name + ".json"
+	color.Cyan("Building a snapshot of the project ...")
+	color.White("💬  Nothing will be tagged or published, the artifacts are left in the " + distDir + " folder.")
 
-	// write the manifest to the file
-	color.Cyan("Creating " + scoopfile_path)
-	mfile, err := os.Create(scoopfile_path)
+	color.Cyan("Running goreleaser release in snapshot mode...")
+	cmd := exec.Command("goreleaser", "release", "--snapshot", "--skip=publish", "--clean")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
 	if err != nil {
-		color.Red("Error creating " + scoopfile_path)
+		fmt.Print("💥 ")
 		color.Red(err.Error())
 		return err
 	}
-	defer mfile.Close()
-
-    color.Blue("🆗 file created.")
-
-	mfile.WriteString(manifest)
-    color.Blue("🆗 scoop file has been written to disk.")
-
-	errors := 0
-
-	color.Cyan("Checking for for existing windows binary release in dist/ folder...")
-
-	// use correct path operator
-	checksum_file := "dist" + string(os.PathSeparator) + name + "_" + version + "_checksums.txt"
-	zip_file :=  name + "_" + version + "_Windows_x86_64.zip"
-	hash := ""
-
-	line, err := findInFile(checksum_file, name+"_"+version+"_Windows_x86_64.zip")
-
-	if err != nil {
-		color.Yellow("⚠  Could not find the checksum file: " + checksum_file)
-		color.White("💬  Make sure you have built the windows binary using gopher release command.")
-		errors++
-	} else {
-		if line == "" {
-			color.Yellow("⚠  Could not find a checksum for " + zip_file + " in the checksum file.")
-			color.White("💬  Make sure you have built the windows binary using gopher release command.")
-			errors++
-		} else {
-			hash = strings.Split(line, " ")[0]
-			color.Blue("🆗 Found a checksum for " + zip_file +" -> " + hash)
-		}
-	}
-
-	if hash != "" {
-		color.Cyan("Adding the sha256 checksum to the manifest file...")
-
-		bin_line := fmt.Sprintf(`    "url": "%s",`, url)
-		new_line := fmt.Sprintf(`    "url": "%s",
-	"hash": "%s",`, url, hash) // indentation intentional, leave it alone
-
-		err = replaceInFile(scoopfile_path, bin_line, new_line)
-		if err != nil {
-			// print a warning
-			color.Yellow("⚠  Could not add the hash to the manifest file.")
-			color.White("💬  You can add it manually later.")
-			errors++
-		} else {
-			color.Blue("🆗 Manifest file updated successfully.")
-		}
-
-	}
+	color.Blue("🆗 goreleaser ran successfully.")
 
-	if errors == 0 {
-		color.Green("✔  Scoop manifest file " + name + ".json created successfully.")
-		return nil
-	} else {
-		color.Green("⚠  Scoop manifest file " + name + ".json created with some warnings.")
-		return fmt.Errorf("scoop manifest creation completed with %d warnings", errors)
-	}
+	err = listDistArtifacts()
+	if err != nil { return err }
 
+	color.Green("✔  Snapshot built successfully.")
+	return nil
 }
 
-// searches the file name.go for a constant named version and returns its value
+// searches the package of the file name.go for a constant named version and returns its value
 func getVersion(filename string) (string, error) {
 
-	line, err := findInFile(filename, "const version")
-
+	loc, err := getVersionLocation(filename)
 	if err != nil {
-		fmt.Print("💥 ")
-		color.Red("Error opening file " + filename)
-		color.Red(err.Error())
 		return "", err
 	}
 
-	quoted_version := strings.Split(line, "=")[1]
-	return strings.Trim(quoted_version, " \""), nil
+	return loc.value, nil
 }
 
 // searches go.mod file for the module name and returns it as string
@@ -856,12 +1306,6 @@ func getName(uri string) string {
 	return parts[len(parts)-1]
 }
 
-// function that takes in a github uri and returns the second to last part of it
-func getUsername(uri string) string {
-	parts := strings.Split(uri, "/")
-	return parts[len(parts)-2]
-}
-
 func banner() {
 	color.Cyan("🐿  Gopher v" + version + "\n")
 }
@@ -878,7 +1322,15 @@ func installProject() error {
 
 	// build it for this system first by running go build
 	color.Cyan("Running go build...")
-	cmd := exec.Command("go", "build")
+	args := []string{"build"}
+
+	// versions kept in git tags are injected at build time
+	if source, _ := getVersionSource(); source == versionSourceGit {
+		loc, _ := findVersionGit()
+		args = append(args, "-ldflags", "-X main.version="+loc.value)
+	}
+
+	cmd := exec.Command("go", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	e := cmd.Run()
@@ -888,14 +1340,14 @@ func installProject() error {
 		color.Red(e.Error())
 	}
 
-    // check if environment variable GOPHER_ISTALLPATH is set
-    color.Cyan("Checking if GOPHER_INSTALLPATH environment variable is set...")
-    installpath := os.Getenv("GOPHER_INSTALLPATH")
+    // check if GOPHER_ISTALLPATH or the install_path setting is set
+    color.Cyan("Looking up the install_path setting...")
+    installpath := getSettingValue("install_path", "")
 
     if installpath == "" {
-        color.Yellow("⚠  GOPHER_INSTALLPATH environment variable is not set.")
+        color.Yellow("⚠  install_path is not set in GOPHER_INSTALLPATH or the gopher config.")
         color.White("💬 You can set it to the directory where you want gopher to install all the binaries.")
-        color.White("💬 Gopher will use ~/bin or %USERPROFILE%\\bin if GOPHER_INSTALLPATH is not set.")
+        color.White("💬 Gopher will use ~/bin or %USERPROFILE%\\bin if install_path is not set.")
     }
 
 	color.Cyan("Checking the os...")
@@ -1130,196 +1582,6 @@ release: build
 	return nil
 }
 
-func createMainFile() error {
-
-	color.Cyan("Getting module name from go.mod file contents...")
-	name := "main"
-
-	color.Cyan("Generating the " + name + ".go file...")
-	content := `package main    
-
-import (
-"os"
-"fmt"
-"path/filepath"
-)
-
-const version = "0.1.0"
-
-func main() {
-	err := run()
-	if err != nil {
-		fmt.Println("Error:", err)
-		os.Exit(1)
-	}
-}
-
-func run() error { 
-
-    if len(os.Args) > 1 {
-        switch os.Args[1] {
-        case "-v", "--version":
-            Version()
-        case "-h", "--help":
-            Usage()
-        default:
-            Usage()
-			return fmt.Errorf("unknown argument: %s", os.Args[1])
-        } 
-    } else {
-        Usage()
-		return fmt.Errorf("no arguments provided")
-    }
-
-	return nil
-}
-
-func Version() {
-    fmt.Println(filepath.Base(os.Args[0]), "version", version)
-}
-
-func Usage() {
-    fmt.Println("Usage:", filepath.Base(os.Args[0]), "[options]")
-    fmt.Println("Options:")
-    fmt.Println("  -v, --version    Print version information and exit")
-    fmt.Println("  -h, --help       Print this message and exit")
-}`
-
-	color.Cyan("Creating the " + name + ".go file on disk...")
-	gfile, err := os.Create(name + ".go")
-	if err != nil {
-		fmt.Print("💥 ")
-		color.Red("Error creating " + name + ".go file")
-		color.Red(err.Error())
-		return err
-	}
-	defer gfile.Close()
-
-	color.Cyan("Writing the " + name + ".go file content to disk...")
-	gfile.WriteString(content)
-	color.Blue("🆗 " + name + ".go file created successfully.")
-	return nil
-}
-
-func createTestFile() error {
-
-	color.Cyan("Getting module name from go.mod file contents...")
-	name, em := getMainFileName()
-
-	if em != nil { return em }
-	filename := name + "_test.go"
-
-	color.Cyan("Generating the " + filename + " file...")
-
-	content := `package main
-
-import (
-	"bytes"
-	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"runtime"
-	"strings"
-	"testing"
-)
-
-var (
-	binName  = "test"
-	cmdPath  string
-	exitCode int
-)
-
-func TestMain(m *testing.M) {
-	if runtime.GOOS == "windows" {
-		binName += ".exe"
-	}
-
-	build := exec.Command("go", "build", "-o", binName)
-	if err := build.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "cannot build %s: %s", binName, err)
-		os.Exit(1)
-	}
-
-	var err error
-	cmdPath, err = filepath.Abs(binName)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "cannot get absolute path to %s: %s", binName, err)
-		os.Exit(1)
-	}
-
-	exitCode = m.Run()
-
-	os.Remove(binName)
-	os.Exit(exitCode)
-}
-
-func TestNoArgs(t *testing.T) {
-	cmd := exec.Command(cmdPath)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Run()
-
-	expected := "Usage:"
-	if !strings.Contains(out.String(), expected) {
-		t.Errorf("expected to contain %q, got %q", expected, out.String())
-	}
-}
-
-func TestCorrectFlags(t *testing.T) {
-	testCases := []struct {
-		args     []string
-		expected string
-	}{
-		{[]string{"-v"}, "version"},
-		{[]string{"--version"}, "version"},
-		{[]string{"-h"}, "Usage:"},
-		{[]string{"--help"}, "Usage:"},
-	}
-
-	for _, tc := range testCases {
-		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
-			cmd := exec.Command(cmdPath, tc.args...)
-			var out bytes.Buffer
-			cmd.Stdout = &out
-			cmd.Run()
-
-			if !strings.Contains(out.String(), tc.expected) {
-				t.Errorf("expected to contain %q, got %q", tc.expected, out.String())
-			}
-		})
-	}
-}
-
-func TestWrongFlag(t *testing.T) {
-	cmd := exec.Command(cmdPath, "-wrong")
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Run()
-
-	expected := "Usage:"
-	if !strings.Contains(out.String(), expected) {
-		t.Errorf("expected to contain %q, got %q", expected, out.String())
-	}
-}`
-
-	color.Cyan("Creating the " + filename + " file on disk...")
-	gfile, err := os.Create(filename)
-	if err != nil {
-		fmt.Print("💥 ")
-		color.Red("Error creating " + filename + " file")
-		color.Red(err.Error())
-		return err
-	}
-	defer gfile.Close()
-
-	color.Cyan("Writing the " + filename + " file content to disk...")
-	gfile.WriteString(content)
-	color.Blue("🆗 " + filename + " file created successfully.")
-	return nil
-}
-
-
 func incString(s string) string {
     n, _ := strconv.Atoi(s)
     return strconv.Itoa(n + 1)
@@ -1327,7 +1589,7 @@ func incString(s string) string {
 
 
 // bump the version number in the version constant of the main file
-func versionBump(what string) error {
+func versionBump(what string, opts BumpOptions) error {
 
     // check for the existence of go.mod
     if _, err := os.Stat("go.mod"); os.IsNotExist(err) {
@@ -1336,66 +1598,226 @@ func versionBump(what string) error {
         return fmt.Errorf("go.mod file not found")
     }
 
-    if what != "major" && what != "minor" && what != "build" && what != "patch"{
+    switch what {
+    case "major", "minor", "build", "patch", "pre", "rc", "beta", "alpha", "release", "auto":
+    case "set":
+        if opts.arg == "" {
+            fmt.Print("💥 ")
+            color.Red("Missing version for bump set. Example: gopher bump set 2.0.0")
+            return fmt.Errorf("missing version for bump set")
+        }
+    default:
         fmt.Print("💥 ")
-        color.Red("Invalid argument for bump subcommand. Use major, minor, or patch.")
+        color.Red("Invalid argument for bump subcommand. Use major, minor, patch, pre, rc, beta, alpha, release, auto or set.")
         printUsage()
         return fmt.Errorf("invalid argument for bump subcommand")
     }
 
+    // when the version comes from git tags the bump is a new tag
+    if source, _ := getVersionSource(); source == versionSourceGit {
+        opts.tag = true
+    }
+
+    // a signature needs a tag to go on
+    if opts.sign {
+        opts.tag = true
+    }
+
+    // a tag implies a commit, otherwise it would point at the old version
+    if opts.tag {
+        opts.commit = true
+    }
+
+    // don't mix the version change with unrelated work
+    in_repo := getGitRepo()
+    if opts.commit && !in_repo {
+        fmt.Print("💥 ")
+        color.Red("The current directory is not a git repository, cannot commit the version change.")
+        return fmt.Errorf("not a git repository")
+    }
+    if in_repo && !opts.allow_dirty {
+        color.Cyan("Checking if the git working tree is clean...")
+        changes, err := getGitChanges()
+        if err != nil { return err }
+        if len(changes) > 0 {
+            fmt.Print("💥 ")
+            color.Red("The git working tree has uncommitted changes. Commit or stash them, or use --allow-dirty.")
+            for _, c := range changes {
+                color.White("  " + c)
+            }
+            return fmt.Errorf("git working tree is dirty")
+        }
+        color.Blue("🆗 Working tree is clean.")
+    }
+
     color.Cyan("Determining the name of the main file...")
 	name, em := getMainFileName()
 	if em != nil { return em }
 	
     // get the current version
-    color.Cyan("Getting current version from " + name + ".go file...")
-    version, ev := getVersion(name + ".go")
+    color.Cyan("Getting current version...")
+    loc, ev := getVersionLocation(name + ".go")
 	if ev != nil { return ev }
+    version := loc.value
 
-    color.Blue("🆗 Current version is " + version)
-    color.Cyan("Bumping the version number...")
+    color.Blue("🆗 Current version is " + version + " (" + loc.String() + ")")
+
+    current, err := parseSemVer(version)
+    if err != nil {
+        fmt.Print("💥 ")
+        color.Red(err.Error())
+        return err
+    }
 
-    // split the version into parts
-    parts := strings.Split(version, ".")
-    major := parts[0]
-    minor := parts[1]
-    build := parts[2]
+    // let the conventional commits since the last tag pick the level
+    if what == "auto" {
+        what, err = getAutoBumpLevel()
+        if err != nil { return err }
+    }
 
-    // increment the build number
-	switch what {
+    color.Cyan("Bumping the version number...")
 
-	case "build", "patch":
-		build = incString(build)
-	case "minor":
-		minor = incString(minor)
-		build = "0"
-	case "major":
-		major = incString(major)
-		minor = "0"
-		build = "0"
-	}
+    var next SemVer
+    if what == "set" {
+        next, err = parseSemVer(opts.arg)
+        if err == nil && compareSemVer(next, current) <= 0 {
+            err = fmt.Errorf("%s is not greater than the current version %s", next, current)
+        }
+    } else {
+        next, err = current.bump(what, opts.arg)
+    }
+    if err != nil {
+        fmt.Print("💥 ")
+        color.Red(err.Error())
+        return err
+    }
+
+    if opts.metadata != "" {
+        next.build = strings.Split(opts.metadata, ".")
+        if err := validIdentifiers(next.build, false); err != nil {
+            fmt.Print("💥 ")
+            color.Red("Invalid build metadata " + opts.metadata + ": " + err.Error())
+            return err
+        }
+    }
 
     // create the new version string
-    new_version := major + "." + minor + "." + build
+    new_version := next.String()
 
     color.Blue("🆗 New version is " + new_version)
 
-    color.Cyan("Replacing the version number in " + name + ".go file...")
+    // git tags have no file to edit, the tag is all there is
+    var touched []string
+    if loc.source != versionSourceGit {
+        color.Cyan("Replacing the version number in " + loc.file + " file...")
 
-    find := "const version = \"" + version + "\""
-    replace := "const version = \"" + new_version + "\""
+        err = writeVersion(loc, new_version)
 
-	err := replaceInFile(name+".go", find, replace)
+        if err != nil {
+            fmt.Print("💥 ")
+            color.Red("Error modifying the source file")
+            color.Red(err.Error())
+            return err
+        }
 
-    if err != nil {
-        fmt.Print("💥 ")
-        color.Red("Error modifying the source file")
-        color.Red(err.Error())
-        return err
+        touched = append(touched, loc.file)
+        color.Blue("🆗 Version number replaced successfully.")
+    }
+
+    // keep the version in the nix flake in step
+    if _, err := os.Stat(flakeFile); err == nil {
+        color.Cyan("Replacing the version number in " + flakeFile + " file...")
+        found, err := updateFlakeVersion(flakeFile, new_version)
+        if err != nil {
+            fmt.Print("💥 ")
+            color.Red("Error modifying " + flakeFile)
+            color.Red(err.Error())
+            return err
+        }
+        if found {
+            touched = append(touched, flakeFile)
+            color.Blue("🆗 " + flakeFile + " updated.")
+        } else {
+            color.Yellow("⚠  Could not find the version in " + flakeFile + ", update it by hand.")
+        }
+    }
+
+    if opts.commit {
+        err = commitBump(touched, version, new_version, opts)
+        if err != nil { return err }
     }
 
-    color.Blue("🆗 Version number replaced successfully.")
     color.Green("✔  Version bumped to " + new_version)
 
 	return nil
 }
+
+// commit the files touched by a bump and optionally tag the commit
+// without files only the tag is created
+func commitBump(files []string, previous string, version string, opts BumpOptions) error {
+
+	data := BumpData{Version: version, Previous: previous}
+
+	message, err := renderString("commit message", getSettingValue("bump.commit_message", opts.message), data)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Invalid commit message template: " + err.Error())
+		return err
+	}
+
+	if len(files) > 0 {
+		color.Cyan("Staging " + strings.Join(files, ", ") + "...")
+		cmd := exec.Command("git", append([]string{"add", "--"}, files...)...)
+		cmd.Stderr = os.Stderr
+		err = cmd.Run()
+		if err != nil {
+			fmt.Print("💥 ")
+			color.Red("Error staging the files: " + err.Error())
+			return err
+		}
+
+		// only commit the bumped files even if other changes are staged
+		color.Cyan("Committing: " + message)
+		cmd = exec.Command("git", append([]string{"commit", "-q", "-m", message, "--"}, files...)...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		err = cmd.Run()
+		if err != nil {
+			fmt.Print("💥 ")
+			color.Red("Error committing the version change: " + err.Error())
+			return err
+		}
+		color.Blue("🆗 Version change committed.")
+	}
+
+	if !opts.tag {
+		return nil
+	}
+
+	tag := "v" + version
+	annotation, err := renderString("tag message", getSettingValue("bump.tag_message", ""), data)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Invalid tag message template: " + err.Error())
+		return err
+	}
+
+	kind := "-a"
+	if opts.sign {
+		kind = "-s"
+	}
+
+	color.Cyan("Creating the " + tag + " tag...")
+	cmd := exec.Command("git", "tag", kind, tag, "-m", annotation)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error creating the " + tag + " tag: " + err.Error())
+		return err
+	}
+
+	color.Green("✔  Tagged " + tag + ". Push it with: git push --follow-tags")
+	return nil
+}
@@ -33,24 +33,6 @@ func TestGetName(t *testing.T) {
 	}
 }
 
-func TestGetUsername(t *testing.T) {
-	testCases := []struct {
-		uri      string
-		expected string
-	}{
-		{"github.com/user/repo", "user"},
-		{"user/repo", "user"},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.uri, func(t *testing.T) {
-			if got := getUsername(tc.uri); got != tc.expected {
-				t.Errorf("expected %q, got %q", tc.expected, got)
-			}
-		})
-	}
-}
-
 func TestIncString(t *testing.T) {
 	testCases := []struct {
 		s        string
@@ -373,7 +355,7 @@ const version = "1.2.3"
 				if err := os.WriteFile(mainGo, []byte(content), 0644); err != nil {
 					t.Fatal(err)
 				}
-				err := versionBump(tc.bumpType)
+				err := versionBump(tc.bumpType, BumpOptions{})
 				if err != nil {
 					t.Fatalf("unexpected error: %v", err)
 				}
@@ -395,7 +377,7 @@ const version = "1.2.3"
 		os.Stdout = w
 		defer func() { os.Stdout = origStdout }()
 
-		err := versionBump("invalid")
+		err := versionBump("invalid", BumpOptions{})
 		if err == nil {
 			t.Error("expected an error for invalid bump type, got nil")
 		}
@@ -411,7 +393,7 @@ const version = "1.2.3"
 		os.Chdir(tmpDir)
 		defer os.Chdir(originalDir)
 
-		err := versionBump("patch")
+		err := versionBump("patch", BumpOptions{})
 		if err == nil {
 			t.Error("expected an error when main.go is not found, got nil")
 		}
@@ -433,7 +415,7 @@ const version = "1.2.3"
 		os.Chdir(tmpDir)
 		defer os.Chdir(originalDir)
 
-		err := versionBump("patch")
+		err := versionBump("patch", BumpOptions{})
 		if err == nil {
 			t.Error("expected an error when go.mod is not found, got nil")
 		}
@@ -459,7 +441,7 @@ const version = "1.2.3"
 		os.Chdir(tmpDir)
 		defer os.Chdir(originalDir)
 
-		err := versionBump("patch")
+		err := versionBump("patch", BumpOptions{})
 		if err == nil {
 			t.Error("expected an error due to read permission denied on main.go, got nil")
 		}
@@ -486,7 +468,7 @@ const version = "1.2.3"
 		os.Chdir(tmpDir)
 		defer os.Chdir(originalDir)
 
-		err := versionBump("patch")
+		err := versionBump("patch", BumpOptions{})
 		if err == nil {
 			t.Error("expected an error due to write permission denied on main.go, got nil")
 		}
@@ -597,106 +579,6 @@ func TestCreateJustfile(t *testing.T) {
 	})
 }
 
-func TestCreateMainFile(t *testing.T) {
-
-	oldOut := color.Output
-	defer func() { color.Output = oldOut }()
-	var buff bytes.Buffer
-	color.Output = &buff
-
-	origStdout := os.Stdout
-	origStderr := os.Stderr
-	_, w, _ := os.Pipe()
-	os.Stdout = w
-	os.Stderr = w
-	defer func() { 
-		os.Stdout = origStdout
-		os.Stderr = origStderr
-	}()
-
-	t.Run("success", func(t *testing.T) {
-		tmpDir := t.TempDir()
-		originalDir, _ := os.Getwd()
-		os.Chdir(tmpDir)
-		defer os.Chdir(originalDir)
-
-		if err := createMainFile(); err != nil {
-			t.Fatalf("createMainFile() failed: %v", err)
-		}
-
-		if _, err := os.Stat("main.go"); os.IsNotExist(err) {
-			t.Error("main.go was not created")
-		}
-	})
-
-	t.Run("module-not-found", func(t *testing.T) {
-		tmpDir := t.TempDir()
-		originalDir, _ := os.Getwd()
-		os.Chdir(tmpDir)
-		defer os.Chdir(originalDir)
-
-		// createMainFile internally calls getModuleName, which will fail if go.mod is not present.
-		// However, createMainFile sets a default name "main" if getModuleName returns an error.
-		// So we expect success here.
-		if err := createMainFile(); err != nil {
-			t.Fatalf("createMainFile() failed: %v", err)
-		}
-		if _, err := os.Stat("main.go"); os.IsNotExist(err) {
-			t.Error("main.go was not created")
-		}
-	})
-}
-
-func TestCreateTestFile(t *testing.T) {
-
-	oldOut := color.Output
-	defer func() { color.Output = oldOut }()
-	var buff bytes.Buffer
-	color.Output = &buff
-
-	origStdout := os.Stdout
-	origStderr := os.Stderr
-	_, w, _ := os.Pipe()
-	os.Stdout = w
-	os.Stderr = w
-	defer func() { 
-		os.Stdout = origStdout
-		os.Stderr = origStderr
-	}()
-
-	t.Run("success", func(t *testing.T) {
-		tmpDir := t.TempDir()
-		originalDir, _ := os.Getwd()
-		os.Chdir(tmpDir)
-		defer os.Chdir(originalDir)
-
-		// create a dummy main.go so getMainFileName() works
-		if err := os.WriteFile("main.go", []byte("package main"), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		if err := createTestFile(); err != nil {
-			t.Fatalf("createTestFile() failed: %v", err)
-		}
-
-		if _, err := os.Stat("main_test.go"); os.IsNotExist(err) {
-			t.Error("main_test.go was not created")
-		}
-	})
-
-	t.Run("no-main-file", func(t *testing.T) {
-		tmpDir := t.TempDir()
-		originalDir, _ := os.Getwd()
-		os.Chdir(tmpDir)
-		defer os.Chdir(originalDir)
-
-		err := createTestFile()
-		if err == nil {
-			t.Error("expected an error when main file is missing, got nil")
-		}
-	})
-}
-
 func TestGetMainFileName(t *testing.T) {
 
 	oldOut := color.Output
@@ -1245,6 +1127,9 @@ func TestInfo(t *testing.T) {
 
 }
 
+// the release settings the release tests run with, the mock git is on main
+const releaseTestConfig = "[release]\nchecks = [\"clean\", \"branch\", \"tag\", \"version\", \"tests\"]\nbranch = \"main\"\n"
+
 func createMockExecutable(t *testing.T, dir, name string) {
 	path := filepath.Join(dir, name)
 	var script string
@@ -1258,7 +1143,8 @@ func createMockExecutable(t *testing.T, dir, name string) {
 			// Handles `go mod init <uri>`
 			script += `if /I "%~1" == "mod" if /I "%~2" == "init" echo module %3 > go.mod`
 		case "git":
-			// No action needed, just exit cleanly.
+			// Handles `git branch --show-current` for the release branch check
+			script += `if /I "%~1" == "branch" echo main`
 		case "goreleaser":
 			// Handles `goreleaser init`
 			script += "if /I \"%~1\"==\"init\" (\r\n"
@@ -1272,7 +1158,7 @@ func createMockExecutable(t *testing.T, dir, name string) {
 		case "go":
 			script += `if [ "$1" = "mod" ] && [ "$2" = "init" ]; then echo "module $3" > go.mod; fi`
 		case "git":
-			// no-op
+			script += `if [ "$1" = "branch" ]; then echo main; fi`
 		case "goreleaser":
 			script += `if [ "$1" = "init" ]; then printf "archives:\n  - name_template: '{{ .ProjectName }}_'" > .goreleaser.yaml; fi`
 		}
@@ -1463,7 +1349,7 @@ func createMockExecutable(t *testing.T, dir, name string) {
 		githubUser := "testuser"
 		uri := fmt.Sprintf("github.com/%s/%s", githubUser, projectName)
 
-		err := createProject(uri)
+		err := createProject(uri, InitOptions{})
 		if err != nil {
 			t.Fatalf("createProject failed unexpectedly: %v", err)
 		}
@@ -1503,7 +1389,7 @@ func createMockExecutable(t *testing.T, dir, name string) {
 		t.Setenv("GOPHER_USERNAME", githubUser)
 		defer os.Unsetenv("GOPHER_USERNAME")
 
-		err := createProject(projectName)
+		err := createProject(projectName, InitOptions{})
 		if err != nil {
 			t.Fatalf("createProject failed unexpectedly: %v", err)
 		}
@@ -1547,7 +1433,12 @@ func createMockExecutable(t *testing.T, dir, name string) {
 		// Make sure GOPHER_USERNAME is not set
 		os.Unsetenv("GOPHER_USERNAME")
 
-		err := createProject(projectName)
+		// Pretend the piped stdin is a terminal
+		origInteractive := isInteractive
+		isInteractive = func() bool { return true }
+		defer func() { isInteractive = origInteractive }()
+
+		err := createProject(projectName, InitOptions{})
 		if err != nil {
 			t.Fatalf("createProject failed unexpectedly: %v", err)
 		}
@@ -1563,6 +1454,125 @@ func createMockExecutable(t *testing.T, dir, name string) {
 		}
 	})
 
+	t.Run("fail-short-name-without-tty", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		tmpBinDir := filepath.Join(tmpDir, "bin")
+		os.Mkdir(tmpBinDir, 0755)
+		createMockExecutable(t, tmpBinDir, "go")
+		createMockExecutable(t, tmpBinDir, "git")
+		createMockExecutable(t, tmpBinDir, "goreleaser")
+		t.Setenv("PATH", tmpBinDir)
+		t.Setenv("GOPHER_USERNAME", "")
+
+		origInteractive := isInteractive
+		isInteractive = func() bool { return false }
+		defer func() { isInteractive = origInteractive }()
+
+		err := createProject("notty-project", InitOptions{})
+		if err == nil {
+			t.Fatal("expected an error when a prompt is needed without a terminal, got nil")
+		}
+		if !strings.Contains(err.Error(), "not a terminal") {
+			t.Errorf("expected a not a terminal error, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "notty-project")); !os.IsNotExist(err) {
+			t.Error("project directory should not be created when init fails early")
+		}
+	})
+
+	t.Run("success-all-flags", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		// only go is available, git and goreleaser are not needed
+		tmpBinDir := filepath.Join(tmpDir, "bin")
+		os.Mkdir(tmpBinDir, 0755)
+		createMockExecutable(t, tmpBinDir, "go")
+		t.Setenv("PATH", tmpBinDir)
+		t.Setenv("GOPHER_USERNAME", "")
+
+		origInteractive := isInteractive
+		isInteractive = func() bool { return false }
+		defer func() { isInteractive = origInteractive }()
+
+		opts := InitOptions{
+			username:      "flaguser",
+			host:          "gitlab.com",
+			origin_style:  "https",
+			no_git:        true,
+			no_goreleaser: true,
+			license:       "mit",
+		}
+		err := createProject("flag-project", opts)
+		if err != nil {
+			t.Fatalf("createProject failed unexpectedly: %v", err)
+		}
+
+		goModContent, err := os.ReadFile(filepath.Join(tmpDir, "flag-project", "go.mod"))
+		if err != nil {
+			t.Fatalf("could not read go.mod: %v", err)
+		}
+		expectedModule := "module gitlab.com/flaguser/flag-project"
+		if !strings.Contains(string(goModContent), expectedModule) {
+			t.Errorf("go.mod should contain %q, but got %q", expectedModule, string(goModContent))
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "flag-project", ".goreleaser.yaml")); !os.IsNotExist(err) {
+			t.Error(".goreleaser.yaml should not be created with --no-goreleaser")
+		}
+		license, err := os.ReadFile(filepath.Join(tmpDir, "flag-project", "LICENSE"))
+		if err != nil {
+			t.Fatalf("LICENSE was not created: %v", err)
+		}
+		if !strings.Contains(string(license), "flaguser") {
+			t.Errorf("expected LICENSE to fall back to the username as author, got %q", string(license))
+		}
+	})
+
+	t.Run("invalid-origin-style", func(t *testing.T) {
+		err := createProject("user/project", InitOptions{origin_style: "ftp"})
+		if err == nil {
+			t.Error("expected an error for an invalid origin style, got nil")
+		}
+	})
+
+	t.Run("existing-dir-needs-yes", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		tmpBinDir := filepath.Join(tmpDir, "bin")
+		os.Mkdir(tmpBinDir, 0755)
+		createMockExecutable(t, tmpBinDir, "go")
+		createMockExecutable(t, tmpBinDir, "git")
+		createMockExecutable(t, tmpBinDir, "goreleaser")
+		t.Setenv("PATH", tmpBinDir)
+
+		origInteractive := isInteractive
+		isInteractive = func() bool { return false }
+		defer func() { isInteractive = origInteractive }()
+
+		os.Mkdir("existing", 0755)
+		os.WriteFile(filepath.Join("existing", "notes.txt"), []byte("keep me"), 0644)
+
+		err := createProject("testuser/existing", InitOptions{})
+		if err == nil {
+			t.Fatal("expected an error for an existing directory without --yes, got nil")
+		}
+
+		os.Chdir(tmpDir)
+		err = createProject("testuser/existing", InitOptions{yes: true})
+		if err != nil {
+			t.Fatalf("createProject failed unexpectedly with --yes: %v", err)
+		}
+	})
+
 	t.Run("fail-check-missing-dep", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		originalDir, _ := os.Getwd()
@@ -1577,7 +1587,7 @@ func createMockExecutable(t *testing.T, dir, name string) {
 		// "goreleaser" is missing
 		t.Setenv("PATH", tmpBinDir)
 
-		err := createProject("some/project")
+		err := createProject("some/project", InitOptions{})
 		if err == nil {
 			t.Error("createProject should have failed due to missing dependency, but it didn't")
 		}
@@ -1746,7 +1756,7 @@ func TestGenerateScoopFile(t *testing.T) {
 		os.Chdir(tmpDir)
 		defer os.Chdir(originalDir)
 
-		err := generateScoopFile()
+		err := generateScoopFile(ScoopOptions{})
 		if err == nil {
 			t.Error("expected an error when dist dir is missing, but got nil")
 		}
@@ -1760,7 +1770,7 @@ func TestGenerateScoopFile(t *testing.T) {
 
 		os.Mkdir("dist", 0755)
 
-		err := generateScoopFile()
+		err := generateScoopFile(ScoopOptions{})
 		if err == nil {
 			t.Error("expected an error when go.mod is missing, but got nil")
 		}
@@ -1787,7 +1797,7 @@ func TestGenerateScoopFile(t *testing.T) {
 		t.Setenv("GOPHER_USERNAME", username)
 		defer os.Unsetenv("GOPHER_USERNAME")
 
-		err := generateScoopFile()
+		err := generateScoopFile(ScoopOptions{})
 		if err != nil {
 			t.Fatalf("generateScoopFile failed: %v", err)
 		}
@@ -1804,12 +1814,28 @@ func TestGenerateScoopFile(t *testing.T) {
     "version": "%s",
     "description": "A new scoop package",
     "homepage": "https://github.com/%s/%s",
-    "checkver": "github",
-    "url": "%s",
-	"hash": "%s",
+    "license": "freeware",
+    "architecture": {
+        "64bit": {
+            "url": "%s",
+            "hash": "%s"
+        }
+    },
     "bin": "%s",
-    "license": "freeware"
-}`, version, username, projectName, expectedURL, hash, projectName+".exe")
+    "checkver": "github",
+    "autoupdate": {
+        "architecture": {
+            "64bit": {
+                "url": "https://github.com/%s/%s/releases/download/v$version/%s_$version_Windows_x86_64.zip"
+            }
+        },
+        "hash": {
+            "url": "$baseurl/%s_$version_checksums.txt",
+            "regex": "$sha256\\s+$basename"
+        }
+    }
+}
+`, version, username, projectName, expectedURL, hash, projectName+".exe", username, projectName, projectName, projectName)
 
 		// Normalize line endings for comparison
 		normalizedExpected := strings.ReplaceAll(expectedContent, "\r\n", "\n")
@@ -1839,7 +1865,7 @@ func TestGenerateScoopFile(t *testing.T) {
 		t.Setenv("GOPHER_USERNAME", username)
 		defer os.Unsetenv("GOPHER_USERNAME")
 
-		err := generateScoopFile()
+		err := generateScoopFile(ScoopOptions{})
 		// Should return a warning, but not a fatal error
 		if err != nil && !strings.Contains(err.Error(), "warnings") {
 			t.Fatalf("generateScoopFile failed unexpectedly: %v", err)
@@ -1851,7 +1877,7 @@ func TestGenerateScoopFile(t *testing.T) {
 		}
 
 		scoopContent, _ := os.ReadFile(scoopFilePath)
-		if strings.Contains(string(scoopContent), `"hash"`) {
+		if strings.Contains(string(scoopContent), `"hash": "`) {
 			t.Error("scoop file should not contain a hash when checksum is missing")
 		}
 	})
@@ -1892,13 +1918,50 @@ func TestRelease(t *testing.T) {
 
 		os.WriteFile("go.mod", []byte("module myreleasetest"), 0644)
 		os.WriteFile("main.go", []byte("package main\nconst version = \"1.0.0\""), 0644)
+		os.WriteFile(projectConfigFile, []byte(releaseTestConfig), 0644)
 
-		err := release()
+		err := release(ReleaseOptions{})
 		if err != nil {
 			t.Fatalf("release failed unexpectedly: %v", err)
 		}
 	})
 
+	t.Run("check-turned-off", func(t *testing.T) {
+		var buff bytes.Buffer
+		color.Output = &buff
+		color.NoColor = true
+
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		tmpBinDir := t.TempDir()
+		createMockExecutable(t, tmpBinDir, "go")
+		createMockExecutable(t, tmpBinDir, "git")
+		createMockExecutable(t, tmpBinDir, "goreleaser")
+		t.Setenv("PATH", tmpBinDir)
+
+		os.WriteFile("go.mod", []byte("module myreleasetest"), 0644)
+		os.WriteFile("main.go", []byte("package main\nconst version = \"1.0.0\""), 0644)
+
+		// the mock git is on main, so the branch check stops the release
+		os.WriteFile(projectConfigFile, []byte("[release]\nchecks = [\"clean\", \"branch\", \"tag\", \"version\", \"tests\"]\nbranch = \"trunk\"\n"), 0644)
+		if err := release(ReleaseOptions{}); err == nil {
+			t.Fatal("expected the branch check to fail, but got nil")
+		}
+
+		// and without it the release goes ahead
+		buff.Reset()
+		os.WriteFile(projectConfigFile, []byte("[release]\nchecks = [\"clean\", \"tag\", \"version\", \"tests\"]\nbranch = \"trunk\"\n"), 0644)
+		if err := release(ReleaseOptions{}); err != nil {
+			t.Fatalf("release failed unexpectedly: %v", err)
+		}
+		if strings.Contains(buff.String(), "on branch") {
+			t.Errorf("expected the branch check not to run, got %q", buff.String())
+		}
+	})
+
 	t.Run("check-fails", func(t *testing.T) {
 		var buff bytes.Buffer
 		color.Output = &buff
@@ -1915,7 +1978,7 @@ func TestRelease(t *testing.T) {
 		// git and goreleaser are missing
 		t.Setenv("PATH", tmpBinDir)
 
-		err := release()
+		err := release(ReleaseOptions{})
 		if err == nil {
 			t.Error("expected an error due to missing dependency, but got nil")
 		}
@@ -1938,7 +2001,7 @@ func TestRelease(t *testing.T) {
 		t.Setenv("PATH", tmpBinDir)
 
 		// No main.go or go.mod
-		err := release()
+		err := release(ReleaseOptions{})
 		if err == nil {
 			t.Error("expected an error when main file is not found, but got nil")
 		}
@@ -1968,8 +2031,10 @@ func TestRelease(t *testing.T) {
 
 		os.WriteFile("go.mod", []byte("module myreleasetest"), 0644)
 		os.WriteFile("main.go", []byte("package main\nconst version = \"1.0.0\""), 0644)
+		// skip the pre-flight checks so the release gets as far as tagging
+		os.WriteFile(projectConfigFile, []byte("[release]\nchecks = []\n"), 0644)
 
-		err := release()
+		err := release(ReleaseOptions{})
 		if err == nil {
 			t.Error("expected an error when git tag fails, but got nil")
 		}
@@ -1999,8 +2064,9 @@ func TestRelease(t *testing.T) {
 
 		os.WriteFile("go.mod", []byte("module myreleasetest"), 0644)
 		os.WriteFile("main.go", []byte("package main\nconst version = \"1.0.0\""), 0644)
+		os.WriteFile(projectConfigFile, []byte(releaseTestConfig), 0644)
 
-		err := release()
+		err := release(ReleaseOptions{})
 		if err == nil {
 			t.Errorf("expected an error when goreleaser fails, but got nil")
 		}
@@ -2026,28 +2092,17 @@ func TestRelease(t *testing.T) {
 		tmpBinDir := t.TempDir()
 		createMockExecutable(t, tmpBinDir, "go")
 
-		// This creates a mock git that will succeed on the first call (tag) and fail on the second (tag -d)
-		counterPath := filepath.Join(tmpBinDir, "git_call_counter")
-		os.WriteFile(counterPath, []byte("0"), 0644)
+		// This creates a mock git that succeeds on everything but deleting the tag (tag -d)
 		gitScript := `
 			@echo off
-			setlocal
-			set COUNTER_FILE=` + counterPath + `
-			set /p CALL_COUNT=<"%COUNTER_FILE%"
-			set /a NEXT_COUNT=CALL_COUNT + 1
-			echo %NEXT_COUNT% > "%COUNTER_FILE%"
-			if %CALL_COUNT% == 0 ( exit /b 0 )
-			if %CALL_COUNT% == 1 ( exit /b 1 )
+			if /I "%~1" == "branch" ( echo main & exit /b 0 )
+			if "%~1" == "tag" if "%~2" == "-d" ( exit /b 1 )
 			exit /b 0
 		`
 		if runtime.GOOS != "windows" {
 			gitScript = `#!/bin/sh
-			COUNTER_FILE=` + counterPath + `
-			CALL_COUNT=$(cat $COUNTER_FILE)
-			NEXT_COUNT=$((CALL_COUNT + 1))
-			echo $NEXT_COUNT > $COUNTER_FILE
-			if [ "$CALL_COUNT" = "0" ]; then exit 0; fi
-			if [ "$CALL_COUNT" = "1" ]; then exit 1; fi
+			if [ "$1" = "branch" ]; then echo main; exit 0; fi
+			if [ "$1" = "tag" ] && [ "$2" = "-d" ]; then exit 1; fi
 			exit 0
 			`
 		}
@@ -2071,12 +2126,72 @@ func TestRelease(t *testing.T) {
 
 		os.WriteFile("go.mod", []byte("module myreleasetest"), 0644)
 		os.WriteFile("main.go", []byte("package main\nconst version = \"1.0.0\""), 0644)
+		os.WriteFile(projectConfigFile, []byte(releaseTestConfig), 0644)
 
-		err := release()
+		err := release(ReleaseOptions{})
 		if err == nil {
 			t.Error("expected an error when both goreleaser and tag deletion fail, but got nil")
 		}
 	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		var buff bytes.Buffer
+		color.Output = &buff
+		color.NoColor = true
+
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		tmpBinDir := t.TempDir()
+		createMockExecutable(t, tmpBinDir, "go")
+
+		// git fails on everything, a snapshot must not need it to tag
+		gitScript := "#!/bin/sh\nexit 1"
+		// goreleaser records its arguments and leaves an archive in dist
+		goreleaserScript := "#!/bin/sh\necho \"$@\" > args.txt\nmkdir -p dist/tool_linux_amd64_v1\nprintf 'archive' > dist/tool_1.0.1-SNAPSHOT-abc_Linux_x86_64.tar.gz\nexit 0"
+		gitPath := filepath.Join(tmpBinDir, "git")
+		goreleaserPath := filepath.Join(tmpBinDir, "goreleaser")
+		if runtime.GOOS == "windows" {
+			gitScript = "@exit 1"
+			goreleaserScript = "@echo off\r\necho %* > args.txt\r\nmkdir dist\\tool_linux_amd64_v1\r\necho archive> dist\\tool_1.0.1-SNAPSHOT-abc_Linux_x86_64.tar.gz\r\nexit /b 0"
+			gitPath += ".bat"
+			goreleaserPath += ".bat"
+		}
+		if err := os.WriteFile(gitPath, []byte(gitScript), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goreleaserPath, []byte(goreleaserScript), 0755); err != nil {
+			t.Fatal(err)
+		}
+		// the mock goreleaser needs mkdir
+		t.Setenv("PATH", tmpBinDir+string(os.PathListSeparator)+originalPath)
+
+		os.WriteFile("go.mod", []byte("module myreleasetest"), 0644)
+		os.WriteFile("main.go", []byte("package main\nconst version = \"1.0.0\""), 0644)
+
+		err := release(ReleaseOptions{snapshot: true})
+		if err != nil {
+			t.Fatalf("release failed unexpectedly: %v", err)
+		}
+
+		args, _ := os.ReadFile("args.txt")
+		if !strings.Contains(string(args), "--snapshot") || !strings.Contains(string(args), "--skip=publish") {
+			t.Errorf("expected goreleaser to run in snapshot mode, got %q", string(args))
+		}
+
+		output := buff.String()
+		if strings.Contains(output, "Tagging") || strings.Contains(output, "Pre-flight") {
+			t.Errorf("expected a snapshot to skip the checks and the tag, got %q", output)
+		}
+		if !strings.Contains(output, "tool_1.0.1-SNAPSHOT-abc_Linux_x86_64.tar.gz") {
+			t.Errorf("expected the artifacts to be listed, got %q", output)
+		}
+		if strings.Contains(output, "tool_linux_amd64_v1") {
+			t.Errorf("expected the build folders to be left out of the list, got %q", output)
+		}
+	})
 }
 
 var (
@@ -2101,11 +2216,25 @@ func TestMain(m *testing.M) {
 		os.Exit(1)
 	}
 
+	// keep the developer's own config and GOPHER_* variables out of the tests
+	configDir, err := os.MkdirTemp("", "gopher-config")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot create the config directory: %s", err)
+		os.Exit(1)
+	}
+	os.Setenv("XDG_CONFIG_HOME", configDir)
+	for _, env := range os.Environ() {
+		if name, _, _ := strings.Cut(env, "="); strings.HasPrefix(name, "GOPHER_") {
+			os.Unsetenv(name)
+		}
+	}
+
 	// run the tests
 	exitCode = m.Run()
 
 	// clean up
 	os.Remove(binName)
+	os.RemoveAll(configDir)
 	os.Exit(exitCode)
 }
 
@@ -2432,7 +2561,11 @@ func TestRunFunction(t *testing.T) {
 		if err := os.WriteFile("go.mod", []byte("module myreleaseproject"), 0644); err != nil {
 			t.Fatal(err)
 		}
-		if err := os.WriteFile("main.go", []byte("package main\nconst version = \"1.0.0\""), 0644); err != nil {
+		// the mock git reports v1.0.0 as the last tag, so release the next version
+		if err := os.WriteFile("main.go", []byte("package main\nconst version = \"1.1.0\""), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(projectConfigFile, []byte(releaseTestConfig), 0644); err != nil {
 			t.Fatal(err)
 		}
 
@@ -2556,12 +2689,28 @@ func TestRunFunction(t *testing.T) {
     "version": "%s",
     "description": "A new scoop package",
     "homepage": "https://github.com/%s/%s",
-    "checkver": "github",
-    "url": "%s",
-	"hash": "%s",
+    "license": "freeware",
+    "architecture": {
+        "64bit": {
+            "url": "%s",
+            "hash": "%s"
+        }
+    },
     "bin": "%s",
-    "license": "freeware"
-}`, version, username, projectName, expectedURL, hash, projectName+".exe")
+    "checkver": "github",
+    "autoupdate": {
+        "architecture": {
+            "64bit": {
+                "url": "https://github.com/%s/%s/releases/download/v$version/%s_$version_Windows_x86_64.zip"
+            }
+        },
+        "hash": {
+            "url": "$baseurl/%s_$version_checksums.txt",
+            "regex": "$sha256\\s+$basename"
+        }
+    }
+}
+`, version, username, projectName, expectedURL, hash, projectName+".exe", username, projectName, projectName, projectName)
 
 		normalizedExpected := strings.ReplaceAll(expectedManifest, "\r\n", "\n")
 		normalizedGot := strings.ReplaceAll(string(scoopContent), "\r\n", "\n")
@@ -2663,3 +2812,145 @@ func TestRunFunction(t *testing.T) {
 
 	
 
+
+func TestVersionBumpGit(t *testing.T) {
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	origStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	os.Stderr = w
+	defer func() {
+		os.Stdout = origStdout
+		os.Stderr = origStderr
+	}()
+
+	setup := func(t *testing.T) func(args ...string) string {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		t.Cleanup(func() { os.Chdir(originalDir) })
+
+		git := setupTestRepo(t)
+		os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+		os.WriteFile("main.go", []byte("package main\n\nconst version = \"1.2.3\"\n"), 0644)
+		os.WriteFile("notes.txt", []byte("notes"), 0644)
+		git("init", "-q")
+		git("add", "-A")
+		git("commit", "-q", "-m", "initial")
+
+		return func(args ...string) string {
+			out, err := exec.Command("git", args...).Output()
+			if err != nil {
+				t.Fatalf("git %v failed: %v", args, err)
+			}
+			return strings.TrimSpace(string(out))
+		}
+	}
+
+	t.Run("commit-and-tag", func(t *testing.T) {
+		git := setup(t)
+
+		if err := versionBump("minor", BumpOptions{tag: true}); err != nil {
+			t.Fatalf("versionBump() failed: %v", err)
+		}
+
+		if got := git("log", "-1", "--format=%s"); got != "chore: release v1.3.0" {
+			t.Errorf("expected commit message %q, got %q", "chore: release v1.3.0", got)
+		}
+		if got := git("show", "--name-only", "--format=", "HEAD"); got != "main.go" {
+			t.Errorf("expected only main.go in the commit, got %q", got)
+		}
+		if got := git("cat-file", "-t", "v1.3.0"); got != "tag" {
+			t.Errorf("expected an annotated v1.3.0 tag, got object type %q", got)
+		}
+		if got := git("status", "--porcelain"); got != "" {
+			t.Errorf("expected a clean tree after the commit, got %q", got)
+		}
+	})
+
+	t.Run("sign-implies-tag", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("the fake gpg is a shell script")
+		}
+		git := setup(t)
+
+		// a fake gpg that signs anything, git only looks for the status line
+		gpg := filepath.Join(t.TempDir(), "gpg")
+		script := "#!/bin/sh\ncat > /dev/null\necho '-----BEGIN PGP SIGNATURE-----'\necho 'fake'\necho '-----END PGP SIGNATURE-----'\necho '[GNUPG:] SIG_CREATED ' >&2\n"
+		os.WriteFile(gpg, []byte(script), 0755)
+		git("config", "gpg.program", gpg)
+
+		if err := versionBump("patch", BumpOptions{sign: true}); err != nil {
+			t.Fatalf("versionBump() failed: %v", err)
+		}
+		if got := git("log", "-1", "--format=%s"); got != "chore: release v1.2.4" {
+			t.Errorf("expected commit message %q, got %q", "chore: release v1.2.4", got)
+		}
+		if got := git("cat-file", "-p", "v1.2.4"); !strings.Contains(got, "BEGIN PGP SIGNATURE") {
+			t.Errorf("expected a signed v1.2.4 tag, got %q", got)
+		}
+	})
+
+	t.Run("message-template", func(t *testing.T) {
+		git := setup(t)
+
+		opts := BumpOptions{commit: true, message: "release {{.Previous}} -> {{.Version}}"}
+		if err := versionBump("patch", opts); err != nil {
+			t.Fatalf("versionBump() failed: %v", err)
+		}
+		if got := git("log", "-1", "--format=%s"); got != "release 1.2.3 -> 1.2.4" {
+			t.Errorf("expected commit message %q, got %q", "release 1.2.3 -> 1.2.4", got)
+		}
+		if got := git("tag", "--list"); got != "" {
+			t.Errorf("expected no tag without --tag, got %q", got)
+		}
+	})
+
+	t.Run("refuses-dirty-tree", func(t *testing.T) {
+		git := setup(t)
+		os.WriteFile("notes.txt", []byte("changed notes"), 0644)
+
+		if err := versionBump("patch", BumpOptions{commit: true}); err == nil {
+			t.Fatal("expected an error on a dirty tree, got nil")
+		}
+		if version, _ := getVersion("main.go"); version != "1.2.3" {
+			t.Errorf("expected the version to stay 1.2.3, got %q", version)
+		}
+
+		// with --allow-dirty the unrelated change is left out of the commit
+		if err := versionBump("patch", BumpOptions{commit: true, allow_dirty: true}); err != nil {
+			t.Fatalf("versionBump() with allow_dirty failed: %v", err)
+		}
+		if got := git("show", "--name-only", "--format=", "HEAD"); got != "main.go" {
+			t.Errorf("expected only main.go in the commit, got %q", got)
+		}
+		if got := git("status", "--porcelain"); got != "M notes.txt" {
+			t.Errorf("expected notes.txt to stay modified, got %q", got)
+		}
+	})
+
+	t.Run("commit-outside-repo", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+		t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(tmpDir))
+
+		os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+		os.WriteFile("main.go", []byte("package main\n\nconst version = \"1.2.3\"\n"), 0644)
+
+		if err := versionBump("patch", BumpOptions{commit: true}); err == nil {
+			t.Error("expected an error outside a git repository, got nil")
+		}
+	})
+}
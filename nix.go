@@ -0,0 +1,302 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// the flake gopher generates and keeps up to date
+const flakeFile = "flake.nix"
+
+// what the flake is made from
+type NixFlake struct {
+	Name        string
+	Version     string
+	Binary      string
+	Description string
+	Homepage    string
+	License     string
+	VendorHash  string
+}
+
+const flakeTemplate = `{
+  description = {{.Description}};
+
+  inputs.nixpkgs.url = "github:NixOS/nixpkgs/nixos-unstable";
+
+  outputs = { self, nixpkgs }:
+    let
+      systems = [ "x86_64-linux" "aarch64-linux" "x86_64-darwin" "aarch64-darwin" ];
+      forAllSystems = nixpkgs.lib.genAttrs systems;
+    in
+    {
+      packages = forAllSystems (system:
+        let
+          pkgs = nixpkgs.legacyPackages.${system};
+        in
+        {
+          default = pkgs.buildGoModule {
+            pname = {{.Name}};
+            version = {{.Version}};
+            src = ./.;
+            vendorHash = {{.VendorHash}};
+            meta = {
+              description = {{.Description}};
+{{- if .Homepage}}
+              homepage = {{.Homepage}};
+{{- end}}
+{{- if .License}}
+              license = pkgs.lib.getLicenseFromSpdxId {{.License}};
+{{- end}}
+              mainProgram = {{.Binary}};
+            };
+          };
+        });
+    };
+}
+`
+
+// matches the version attribute of the derivation in a flake
+var flakeVersionPattern = regexp.MustCompile(`(?m)^(\s*version\s*=\s*")[^"]*(";)`)
+
+// quote a nix string, escaping what nix would interpolate
+func nixString(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`).Replace(s)
+	return `"` + s + `"`
+}
+
+// the flake values as nix expressions, empty values stay empty so the template can leave them out
+// a missing vendorHash is null
+func (f NixFlake) expressions() NixFlake {
+	quote := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return nixString(s)
+	}
+	e := NixFlake{
+		Name:        quote(f.Name),
+		Version:     quote(f.Version),
+		Binary:      quote(f.Binary),
+		Description: quote(f.Description),
+		Homepage:    quote(f.Homepage),
+		License:     quote(f.License),
+		VendorHash:  quote(f.VendorHash),
+	}
+	if e.VendorHash == "" {
+		e.VendorHash = "null"
+	}
+	return e
+}
+
+// matches the major version suffix of a module path, e.g. /v2
+var majorVersionPattern = regexp.MustCompile(`/v[0-9]+$`)
+
+// go install names the binary after the module, without a major version suffix
+// e.g. github.com/user/tool/v2 builds tool
+func getBinaryName(module string) string {
+	return getName(majorVersionPattern.ReplaceAllString(strings.Trim(module, "/"), ""))
+}
+
+// write a string the way nix archives do, length first and padded to 8 bytes
+func writeNarString(w io.Writer, s string) {
+	binary.Write(w, binary.LittleEndian, uint64(len(s)))
+	io.WriteString(w, s)
+	if pad := len(s) % 8; pad != 0 {
+		w.Write(make([]byte, 8-pad))
+	}
+}
+
+// serialize a file tree in the nix archive format
+// see https://nix.dev/manual/nix/latest/protocols/nix-archive
+func writeNar(w io.Writer, path string) error {
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	writeNarString(w, "(")
+	writeNarString(w, "type")
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		writeNarString(w, "symlink")
+		writeNarString(w, "target")
+		writeNarString(w, target)
+
+	case info.IsDir():
+		writeNarString(w, "directory")
+		// ReadDir sorts by name, which is the order nix wants
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			writeNarString(w, "entry")
+			writeNarString(w, "(")
+			writeNarString(w, "name")
+			writeNarString(w, e.Name())
+			writeNarString(w, "node")
+			if err := writeNar(w, filepath.Join(path, e.Name())); err != nil {
+				return err
+			}
+			writeNarString(w, ")")
+		}
+
+	default:
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		writeNarString(w, "regular")
+		if info.Mode()&0111 != 0 {
+			writeNarString(w, "executable")
+			writeNarString(w, "")
+		}
+		writeNarString(w, "contents")
+		writeNarString(w, string(content))
+	}
+
+	writeNarString(w, ")")
+	return nil
+}
+
+// the nix hash of a file tree in the SRI format, e.g. sha256-...
+func narHash(path string) (string, error) {
+	h := sha256.New()
+	writeNarString(h, "nix-archive-1")
+	if err := writeNar(h, path); err != nil {
+		return "", err
+	}
+	return "sha256-" + base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// compute the vendorHash buildGoModule checks the vendored dependencies against
+// the modules come from the local module cache, nothing is downloaded
+// returns an empty string when the project has no dependencies
+func getVendorHash() (string, error) {
+
+	tmp, err := os.MkdirTemp("", "gopher-vendor")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	vendor := filepath.Join(tmp, "vendor")
+	cmd := exec.Command("go", "mod", "vendor", "-o", vendor)
+	cmd.Env = append(os.Environ(), "GOPROXY=off", "GOFLAGS=-mod=mod")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("go mod vendor failed: %s", strings.TrimSpace(string(out)))
+	}
+
+	// buildGoModule wants null when there is nothing to vendor
+	if _, err := os.Stat(vendor); os.IsNotExist(err) {
+		return "", nil
+	}
+	return narHash(vendor)
+}
+
+// update the version of the derivation in the flake
+func updateFlakeVersion(file string, version string) (bool, error) {
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+
+	loc := flakeVersionPattern.FindSubmatchIndex(content)
+	if loc == nil {
+		return false, nil
+	}
+
+	updated := string(content[:loc[3]]) + version + string(content[loc[4]:])
+	return true, os.WriteFile(file, []byte(updated), 0644)
+}
+
+// generate a flake.nix that builds the project with buildGoModule
+func generateFlake() error {
+
+	color.Cyan("Generating " + flakeFile + "...")
+
+	color.Cyan("Getting module string from go.mod file...")
+	module, err := getModule()
+	if err != nil {
+		return err
+	}
+
+	mainfile, err := getMainFileName()
+	if err != nil {
+		return err
+	}
+	version, err := getVersion(mainfile + ".go")
+	if err != nil {
+		return err
+	}
+
+	flake := NixFlake{
+		Name:        getBinaryName(module),
+		Version:     version,
+		Binary:      getBinaryName(module),
+		Description: getProjectDescription(),
+		License:     detectLicense(),
+	}
+	color.Blue("🆗 Package " + flake.Name + " " + flake.Version + ", binary " + flake.Binary)
+
+	if host, owner, name := parseModule(majorVersionPattern.ReplaceAllString(module, "")); owner != "" && host != "" {
+		flake.Homepage = getForge(host).homepage(owner, name)
+	}
+
+	if flake.Description == "" {
+		color.Cyan("Adding generic description, you can edit it later...")
+		flake.Description = "A new Go package"
+	}
+
+	color.Cyan("Computing the vendorHash from the module cache...")
+	flake.VendorHash, err = getVendorHash()
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Could not vendor the dependencies from the module cache")
+		color.Red(err.Error())
+		color.White("💬  Run go mod download and try again.")
+		return err
+	}
+	if flake.VendorHash == "" {
+		color.Blue("🆗 No dependencies, vendorHash is null.")
+	} else {
+		color.Blue("🆗 vendorHash: " + flake.VendorHash)
+	}
+
+	content, err := renderString(flakeFile, flakeTemplate, flake.expressions())
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return err
+	}
+
+	err = os.WriteFile(flakeFile, []byte(content), 0644)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error creating " + flakeFile)
+		color.Red(err.Error())
+		return err
+	}
+
+	color.Green("✔  " + flakeFile + " created successfully. Build it with: nix build")
+	return nil
+}
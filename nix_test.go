@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestNixString(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"tool", `"tool"`},
+		{`a "quoted" tool`, `"a \"quoted\" tool"`},
+		{"costs ${price}", `"costs \${price}"`},
+		{`back\slash`, `"back\\slash"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			if got := nixString(tc.value); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetBinaryName(t *testing.T) {
+	testCases := []struct {
+		module   string
+		expected string
+	}{
+		{"tool", "tool"},
+		{"github.com/user/tool", "tool"},
+		{"github.com/user/tool/v2", "tool"},
+		{"github.com/user/v2tool", "v2tool"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.module, func(t *testing.T) {
+			if got := getBinaryName(tc.module); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNarHash(t *testing.T) {
+
+	tmpDir := t.TempDir()
+	os.Mkdir(filepath.Join(tmpDir, "tree"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "tree", "b.txt"), []byte("hello"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "tree", "a.sh"), []byte("#!/bin/sh\n"), 0755)
+
+	// the archive spelled out by hand, entries sorted by name
+	var nar bytes.Buffer
+	str := func(s string) {
+		binary.Write(&nar, binary.LittleEndian, uint64(len(s)))
+		nar.WriteString(s)
+		nar.Write(make([]byte, (8-len(s)%8)%8))
+	}
+	for _, s := range []string{
+		"nix-archive-1", "(", "type", "directory",
+		"entry", "(", "name", "a.sh", "node", "(", "type", "regular", "executable", "", "contents", "#!/bin/sh\n", ")", ")",
+		"entry", "(", "name", "b.txt", "node", "(", "type", "regular", "contents", "hello", ")", ")",
+		")",
+	} {
+		str(s)
+	}
+	sum := sha256.Sum256(nar.Bytes())
+	expected := "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+
+	got, err := narHash(filepath.Join(tmpDir, "tree"))
+	if err != nil {
+		t.Fatalf("narHash() failed: %v", err)
+	}
+	if got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestUpdateFlakeVersion(t *testing.T) {
+
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, flakeFile)
+
+	os.WriteFile(file, []byte("{\n  description = \"version = \\\"x\\\"\";\n  pname = \"tool\";\n    version = \"1.0.0\";\n  other.version = \"9\";\n}\n"), 0644)
+	found, err := updateFlakeVersion(file, "1.1.0")
+	if err != nil || !found {
+		t.Fatalf("expected the version to be updated, got %v, %v", found, err)
+	}
+	content, _ := os.ReadFile(file)
+	if !strings.Contains(string(content), "    version = \"1.1.0\";\n  other.version = \"9\";") {
+		t.Errorf("expected only the version attribute to change, got %q", string(content))
+	}
+
+	os.WriteFile(file, []byte("{ }\n"), 0644)
+	if found, err := updateFlakeVersion(file, "1.1.0"); err != nil || found {
+		t.Errorf("expected no version to be found, got %v, %v", found, err)
+	}
+}
+
+func TestGenerateFlake(t *testing.T) {
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	os.WriteFile("go.mod", []byte("module github.com/user/tool/v2\n\ngo 1.21\n"), 0644)
+	os.WriteFile("main.go", []byte("package main\n\nconst version = \"2.0.0\"\n\nfunc main() { println(version) }\n"), 0644)
+	os.WriteFile("LICENSE", []byte("SPDX-License-Identifier: MIT\n"), 0644)
+	os.WriteFile("README.md", []byte("# tool\n\nA tool that does things.\n"), 0644)
+
+	if err := generateFlake(); err != nil {
+		t.Fatalf("generateFlake() failed: %v\n%s", err, buff.String())
+	}
+
+	content, err := os.ReadFile(flakeFile)
+	if err != nil {
+		t.Fatalf("expected %s to be written: %v", flakeFile, err)
+	}
+
+	expected := `          default = pkgs.buildGoModule {
+            pname = "tool";
+            version = "2.0.0";
+            src = ./.;
+            vendorHash = null;
+            meta = {
+              description = "A tool that does things.";
+              homepage = "https://github.com/user/tool";
+              license = pkgs.lib.getLicenseFromSpdxId "MIT";
+              mainProgram = "tool";
+            };
+          };
+`
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("expected the derivation:\n%s\ngot:\n%s", expected, string(content))
+	}
+
+	t.Run("bump-updates-the-flake", func(t *testing.T) {
+		if err := versionBump("minor", BumpOptions{}); err != nil {
+			t.Fatalf("versionBump() failed: %v", err)
+		}
+		content, _ := os.ReadFile(flakeFile)
+		if !strings.Contains(string(content), "version = \"2.1.0\";") {
+			t.Errorf("expected the flake version to be bumped, got:\n%s", string(content))
+		}
+	})
+}
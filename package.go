@@ -0,0 +1,231 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// a linux package of the project binary for one architecture
+type LinuxPackage struct {
+	name        string
+	version     string
+	arch        string
+	maintainer  string
+	homepage    string
+	license     string
+	description string
+	mtime       time.Time
+	files       []PackageFile
+}
+
+// a file installed by a package
+type PackageFile struct {
+	path    string
+	mode    int64
+	data    []byte
+	license bool
+}
+
+// a package format gopher can build
+type PackageFormat struct {
+	name string
+	// the architecture name the format uses for a target, false if it has none
+	arch func(t Target) (string, bool)
+	// where the license file of the project is installed
+	licensePath func(name string, file string) string
+	// the file name of the package
+	file  func(p LinuxPackage) string
+	build func(p LinuxPackage) ([]byte, error)
+}
+
+var packageFormats = []PackageFormat{
+	{"deb", debArch, debLicensePath, debFileName, buildDeb},
+	{"rpm", rpmArch, rpmLicensePath, rpmFileName, buildRpm},
+}
+
+// the linux targets packages are built for, when their archives are in dist
+var packageTargets = []Target{
+	{goos: "linux", goarch: "amd64"},
+	{goos: "linux", goarch: "386"},
+	{goos: "linux", goarch: "arm64"},
+	{goos: "linux", goarch: "arm", goarm: "6"},
+	{goos: "linux", goarch: "arm", goarm: "7"},
+}
+
+// find a package format by name
+func findPackageFormat(name string) (PackageFormat, bool) {
+	for _, f := range packageFormats {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return PackageFormat{}, false
+}
+
+// the names of the package formats
+func getPackageFormatNames() []string {
+	var names []string
+	for _, f := range packageFormats {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+// both deb and rpm sort a ~ before anything, so 1.0.0-rc1 becomes 1.0.0~rc1
+// a dash would be read as the package revision
+func packageVersion(version string) string {
+	return strings.ReplaceAll(version, "-", "~")
+}
+
+// read a file from a tar.gz archive by its base name
+// returns the contents and the modification time recorded in the archive
+func extractArchiveFile(archive string, name string) ([]byte, time.Time, error) {
+
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	tr := tar.NewReader(gr)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if h.Typeflag == tar.TypeReg && path.Base(h.Name) == name {
+			data, err := io.ReadAll(tr)
+			return data, h.ModTime, err
+		}
+	}
+	return nil, time.Time{}, fmt.Errorf("%s does not contain %s", archive, name)
+}
+
+// wrap the linux binaries in dist into packages of the given format
+func packageProject(format string) error {
+
+	pf, ok := findPackageFormat(format)
+	if !ok {
+		fmt.Print("💥 ")
+		color.Red("Unknown package format " + format + ". Use one of: " + strings.Join(getPackageFormatNames(), ", "))
+		return fmt.Errorf("unknown package format: %s", format)
+	}
+
+	color.Cyan("Building " + format + " packages...")
+
+	info, err := getReleaseInfo()
+	if err != nil {
+		return err
+	}
+
+	maintainer := getMaintainer()
+	if maintainer == "" {
+		color.Yellow("⚠  user.name and user.email are not set in git config, using " + info.owner + " as the maintainer.")
+		maintainer = info.owner
+	}
+	color.Blue("🆗 Maintainer: " + maintainer)
+
+	license := info.license
+	if license == "" {
+		color.Yellow("⚠  No LICENSE file found, the packages will say Proprietary.")
+		license = "Proprietary"
+	}
+
+	description := getProjectDescription()
+	if description == "" {
+		color.Cyan("Adding generic description, you can edit it later...")
+		description = "A new " + format + " package"
+	}
+
+	var license_text []byte
+	license_file := findLicenseFile()
+	if license_file != "" {
+		license_text, err = os.ReadFile(license_file)
+		if err != nil {
+			fmt.Print("💥 ")
+			color.Red(err.Error())
+			return err
+		}
+	}
+
+	built := 0
+	for _, t := range packageTargets {
+		arch, ok := pf.arch(t)
+		if !ok {
+			continue
+		}
+
+		archive := filepath.Join(distDir, archiveName(info.name, info.version, t)+".tar.gz")
+		if _, err := os.Stat(archive); err != nil {
+			continue
+		}
+
+		binary, mtime, err := extractArchiveFile(archive, info.name)
+		if err != nil {
+			fmt.Print("💥 ")
+			color.Red("Could not read the binary from " + archive)
+			color.Red(err.Error())
+			return err
+		}
+
+		pkg := LinuxPackage{
+			name:        info.name,
+			version:     packageVersion(info.version),
+			arch:        arch,
+			maintainer:  maintainer,
+			homepage:    info.homepage,
+			license:     license,
+			description: description,
+			mtime:       mtime,
+			files:       []PackageFile{{path: "/usr/bin/" + info.name, mode: 0755, data: binary}},
+		}
+		if license_text != nil {
+			pkg.files = append(pkg.files, PackageFile{path: pf.licensePath(info.name, license_file), mode: 0644, data: license_text, license: true})
+		}
+
+		content, err := pf.build(pkg)
+		if err != nil {
+			fmt.Print("💥 ")
+			color.Red("Error building the " + arch + " package")
+			color.Red(err.Error())
+			return err
+		}
+
+		file := filepath.Join(distDir, pf.file(pkg))
+		err = os.WriteFile(file, content, 0644)
+		if err != nil {
+			fmt.Print("💥 ")
+			color.Red("Error creating " + file)
+			color.Red(err.Error())
+			return err
+		}
+		color.Blue("🆗 " + file)
+		built++
+	}
+
+	if built == 0 {
+		fmt.Print("💥 ")
+		color.Red("There are no Linux archives for version " + info.version + " in " + distDir)
+		color.White("💬  Make sure you have built the project using gopher release or gopher build --release")
+		return fmt.Errorf("no linux archives in %s", distDir)
+	}
+
+	color.Green(fmt.Sprintf("✔  Built %d %s packages.", built, format))
+	return nil
+}
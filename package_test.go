@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestPackageVersion(t *testing.T) {
+	testCases := []struct {
+		version  string
+		expected string
+	}{
+		{"1.0.0", "1.0.0"},
+		{"1.0.0-rc.1", "1.0.0~rc.1"},
+		{"1.0.0+build.5", "1.0.0+build.5"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			if got := packageVersion(tc.version); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestExtractArchiveFile(t *testing.T) {
+
+	tmpDir := t.TempDir()
+	os.Mkdir(filepath.Join(tmpDir, "bin"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "bin", "tool"), []byte("binary"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# tool\n"), 0644)
+	archive := filepath.Join(tmpDir, "tool.tar.gz")
+	if err := writeTarGz(archive, []string{filepath.Join(tmpDir, "bin", "tool"), filepath.Join(tmpDir, "README.md")}); err != nil {
+		t.Fatalf("failed to write the archive: %v", err)
+	}
+
+	data, _, err := extractArchiveFile(archive, "tool")
+	if err != nil {
+		t.Fatalf("extractArchiveFile() failed: %v", err)
+	}
+	if string(data) != "binary" {
+		t.Errorf("expected the binary, got %q", string(data))
+	}
+
+	if _, _, err := extractArchiveFile(archive, "other"); err == nil {
+		t.Error("expected an error for a file not in the archive, got nil")
+	}
+}
+
+func TestPackageProject(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	gitconfig := filepath.Join(t.TempDir(), "gitconfig")
+	os.WriteFile(gitconfig, []byte("[user]\n\tname = Jane Doe\n\temail = jane@example.com\n"), 0644)
+	t.Setenv("GIT_CONFIG_GLOBAL", gitconfig)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	os.Mkdir(distDir, 0755)
+	os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+	os.WriteFile("main.go", []byte("package main\nconst version = \"2.0.0\""), 0644)
+	os.WriteFile("LICENSE", []byte("SPDX-License-Identifier: MIT\n"), 0644)
+
+	t.Run("unknown-format", func(t *testing.T) {
+		if err := packageProject("snap"); err == nil {
+			t.Error("expected an error for an unknown format, got nil")
+		}
+	})
+
+	t.Run("no-archives", func(t *testing.T) {
+		if err := packageProject("deb"); err == nil {
+			t.Error("expected an error without linux archives, got nil")
+		}
+	})
+
+	os.Mkdir("bin", 0755)
+	os.WriteFile(filepath.Join("bin", "tool"), []byte("binary"), 0755)
+	for _, archive := range []string{"tool_2.0.0_Linux_x86_64.tar.gz", "tool_2.0.0_Linux_arm64.tar.gz"} {
+		writeTarGz(filepath.Join(distDir, archive), []string{filepath.Join("bin", "tool")})
+	}
+
+	testCases := []struct {
+		format   string
+		expected []string
+	}{
+		{"deb", []string{"tool_2.0.0_amd64.deb", "tool_2.0.0_arm64.deb"}},
+		{"rpm", []string{"tool-2.0.0-1.x86_64.rpm", "tool-2.0.0-1.aarch64.rpm"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			if err := packageProject(tc.format); err != nil {
+				t.Fatalf("packageProject() failed: %v", err)
+			}
+			for _, file := range tc.expected {
+				if _, err := os.Stat(filepath.Join(distDir, file)); err != nil {
+					t.Errorf("expected %s to be written: %v", file, err)
+				}
+			}
+			if !strings.Contains(buff.String(), "Maintainer: Jane Doe <jane@example.com>") {
+				t.Errorf("expected the maintainer from git config, got %q", buff.String())
+			}
+		})
+	}
+}
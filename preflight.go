@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// what the pre-flight checks need to know about the release
+type ReleaseContext struct {
+	version string
+	tag     string
+	source  string
+}
+
+// a single pre-flight check, it returns what it found and an error when it fails
+type Check struct {
+	name string
+	run  func(ctx ReleaseContext) (string, error)
+}
+
+// the checks gopher knows, run in this order
+var releaseChecks = []Check{
+	{name: "clean", run: checkClean},
+	{name: "branch", run: checkBranch},
+	{name: "tag", run: checkTag},
+	{name: "version", run: checkVersion},
+	{name: "tests", run: checkTests},
+}
+
+// the result of running one check
+type CheckResult struct {
+	name    string
+	message string
+	err     error
+}
+
+// find a check by its name
+func findCheck(name string) (Check, bool) {
+	for _, c := range releaseChecks {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return Check{}, false
+}
+
+// get the list of checks to run from the release.checks setting
+func getReleaseChecks() ([]Check, error) {
+
+	var checks []Check
+	for _, name := range strings.Split(getSettingValue("release.checks", ""), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		c, ok := findCheck(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown release check %q in release.checks", name)
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+// check if a tag exists and if it points at the current commit
+func getTagState(tag string) (exists bool, at_head bool) {
+
+	output, err := exec.Command("git", "tag", "--list", tag).Output()
+	if err != nil || strings.TrimSpace(string(output)) != tag {
+		return false, false
+	}
+
+	tagged, err := exec.Command("git", "rev-list", "-n", "1", tag).Output()
+	if err != nil {
+		return true, false
+	}
+	head, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return true, false
+	}
+	return true, strings.TrimSpace(string(tagged)) == strings.TrimSpace(string(head))
+}
+
+// the working tree must not have uncommitted changes
+func checkClean(ctx ReleaseContext) (string, error) {
+	changes, err := getGitChanges()
+	if err != nil {
+		return "", err
+	}
+	if len(changes) > 0 {
+		return "", fmt.Errorf("%d uncommitted changes", len(changes))
+	}
+	return "working tree is clean", nil
+}
+
+// the current branch must be one of the release branches
+func checkBranch(ctx ReleaseContext) (string, error) {
+	branch, err := getGitBranch()
+	if err != nil {
+		return "", err
+	}
+	allowed := strings.Split(getSettingValue("release.branch", ""), ",")
+	for _, b := range allowed {
+		if strings.TrimSpace(b) == branch {
+			return "on branch " + branch, nil
+		}
+	}
+	return "", fmt.Errorf("on branch %q, releases are made from %s", branch, strings.Join(allowed, " or "))
+}
+
+// the tag must not exist, unless it was already made for this commit (e.g. by bump --tag)
+func checkTag(ctx ReleaseContext) (string, error) {
+	exists, at_head := getTagState(ctx.tag)
+	switch {
+	case !exists && ctx.source == versionSourceGit:
+		return "", fmt.Errorf("%s does not exist, create it with gopher bump", ctx.tag)
+	case !exists:
+		return ctx.tag + " is available", nil
+	case at_head:
+		return ctx.tag + " already points at HEAD", nil
+	}
+	return "", fmt.Errorf("%s already exists on another commit", ctx.tag)
+}
+
+// the version must be greater than the last tag
+func checkVersion(ctx ReleaseContext) (string, error) {
+
+	last := getGitTag()
+	if last == "unknown" || last == "" {
+		return "no previous tag", nil
+	}
+
+	// the version was tagged already, compare with the tag before it
+	if last == ctx.tag {
+		if _, at_head := getTagState(ctx.tag); at_head {
+			output, err := exec.Command("git", "describe", "--tags", "--abbrev=0", ctx.tag+"^").Output()
+			if err != nil {
+				return "no previous tag", nil
+			}
+			last = strings.TrimSpace(string(output))
+		}
+	}
+
+	current, err := parseSemVer(ctx.version)
+	if err != nil {
+		return "", err
+	}
+	previous, err := parseSemVer(last)
+	if err != nil {
+		return "", fmt.Errorf("last tag %s is not a version: %w", last, err)
+	}
+	if compareSemVer(current, previous) <= 0 {
+		return "", fmt.Errorf("%s is not greater than the last tag %s", ctx.version, last)
+	}
+	return ctx.version + " > " + last, nil
+}
+
+// the test suite must pass
+func checkTests(ctx ReleaseContext) (string, error) {
+	cmd := exec.Command("go", "test", "./...")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		// show what failed, the table only has room for a summary
+		os.Stderr.Write(output)
+		return "", fmt.Errorf("go test ./... failed")
+	}
+	return "all tests pass", nil
+}
+
+// run the checks and print a pass/fail table
+// returns an error if any of them failed
+func runReleaseChecks(ctx ReleaseContext, checks []Check) error {
+
+	color.Cyan("Running the pre-flight checks...")
+
+	var results []CheckResult
+	failed := 0
+	for _, c := range checks {
+		message, err := c.run(ctx)
+		if err != nil {
+			failed++
+		}
+		results = append(results, CheckResult{name: c.name, message: message, err: err})
+	}
+
+	fmt.Println()
+	color.White("📋 Pre-flight checks:")
+	for _, r := range results {
+		if r.err != nil {
+			color.Red(fmt.Sprintf("  ✘  %-8s %s", r.name, r.err.Error()))
+		} else {
+			color.Green(fmt.Sprintf("  ✔  %-8s %s", r.name, r.message))
+		}
+	}
+	fmt.Println()
+
+	if failed > 0 {
+		fmt.Print("💥 ")
+		color.Red(fmt.Sprintf("%d of %d pre-flight checks failed.", failed, len(results)))
+		return fmt.Errorf("%d pre-flight checks failed", failed)
+	}
+
+	color.Blue(fmt.Sprintf("🆗 All %d pre-flight checks passed.", len(results)))
+	return nil
+}
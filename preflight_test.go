@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestGetReleaseChecks(t *testing.T) {
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	testCases := []struct {
+		name     string
+		config   string
+		expected []string
+		wantErr  bool
+	}{
+		{"default", "", []string{"clean", "branch", "tag", "version", "tests"}, false},
+		{"custom-order", "[release]\nchecks = \"tests, clean\"\n", []string{"tests", "clean"}, false},
+		{"array", "[release]\nchecks = [\"tag\"]\n", []string{"tag"}, false},
+		{"none", "[release]\nchecks = []\n", nil, false},
+		{"unknown", "[release]\nchecks = [\"clean\", \"lint\"]\n", nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Remove(projectConfigFile)
+			if tc.config != "" {
+				os.WriteFile(projectConfigFile, []byte(tc.config), 0644)
+			}
+
+			checks, err := getReleaseChecks()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+
+			var names []string
+			for _, c := range checks {
+				names = append(names, c.name)
+			}
+			if strings.Join(names, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("expected checks %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestReleaseChecks(t *testing.T) {
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	origStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	os.Stderr = w
+	defer func() {
+		os.Stdout = origStdout
+		os.Stderr = origStderr
+	}()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	git := setupTestRepo(t)
+
+	// the go line keeps the tests check from rewriting go.mod
+	os.WriteFile("go.mod", []byte("module github.com/user/tool\n\ngo 1.21\n"), 0644)
+	os.WriteFile("main.go", []byte("package main\n\nconst version = \"1.0.0\"\n\nfunc main() {}\n"), 0644)
+	git("init", "-q", "-b", "main")
+	git("add", "-A")
+	git("commit", "-q", "-m", "feat: first release")
+	git("tag", "v1.0.0")
+	os.WriteFile("main.go", []byte("package main\n\nconst version = \"1.1.0\"\n\nfunc main() {}\n"), 0644)
+	git("commit", "-q", "-am", "chore: release v1.1.0")
+
+	t.Run("ready", func(t *testing.T) {
+		buff.Reset()
+		if err := release(ReleaseOptions{check: true}); err != nil {
+			t.Fatalf("release() failed: %v\n%s", err, buff.String())
+		}
+		if !strings.Contains(buff.String(), "Ready to release v1.1.0") {
+			t.Errorf("expected the checks to pass, got %q", buff.String())
+		}
+		if exists, _ := getTagState("v1.1.0"); exists {
+			t.Error("expected --check not to create the tag")
+		}
+	})
+
+	t.Run("dirty-tree", func(t *testing.T) {
+		os.WriteFile("main.go", []byte("package main\n\nconst version = \"1.1.0\"\n\nfunc main() { println() }\n"), 0644)
+		defer git("checkout", "--", "main.go")
+
+		buff.Reset()
+		if err := release(ReleaseOptions{check: true}); err == nil {
+			t.Error("expected an error with uncommitted changes, got nil")
+		}
+		if !strings.Contains(buff.String(), "✘  clean") {
+			t.Errorf("expected the clean check to fail, got %q", buff.String())
+		}
+	})
+
+	t.Run("wrong-branch", func(t *testing.T) {
+		git("checkout", "-q", "-b", "feature")
+		defer git("checkout", "-q", "main")
+
+		buff.Reset()
+		if err := release(ReleaseOptions{check: true}); err == nil {
+			t.Error("expected an error on a feature branch, got nil")
+		}
+		if !strings.Contains(buff.String(), "✘  branch") {
+			t.Errorf("expected the branch check to fail, got %q", buff.String())
+		}
+	})
+
+	t.Run("branch-setting", func(t *testing.T) {
+		git("checkout", "-q", "-b", "release/1.x")
+		defer git("checkout", "-q", "main")
+		os.WriteFile(projectConfigFile, []byte("[release]\nbranch = \"main, release/1.x\"\n"), 0644)
+		defer os.Remove(projectConfigFile)
+
+		if _, err := checkBranch(ReleaseContext{}); err != nil {
+			t.Errorf("expected release/1.x to be a release branch, got %v", err)
+		}
+	})
+
+	t.Run("tag-on-another-commit", func(t *testing.T) {
+		git("tag", "v1.1.0", "HEAD~1")
+		defer git("tag", "-d", "v1.1.0")
+
+		buff.Reset()
+		if err := release(ReleaseOptions{check: true}); err == nil {
+			t.Error("expected an error when the tag exists, got nil")
+		}
+		if !strings.Contains(buff.String(), "already exists on another commit") {
+			t.Errorf("expected the tag check to fail, got %q", buff.String())
+		}
+	})
+
+	t.Run("tag-at-head", func(t *testing.T) {
+		git("tag", "v1.1.0")
+		defer git("tag", "-d", "v1.1.0")
+
+		if _, err := checkTag(ReleaseContext{version: "1.1.0", tag: "v1.1.0"}); err != nil {
+			t.Errorf("expected a tag made by bump --tag to pass, got %v", err)
+		}
+		if _, err := checkVersion(ReleaseContext{version: "1.1.0", tag: "v1.1.0"}); err != nil {
+			t.Errorf("expected the version to be compared with the tag before it, got %v", err)
+		}
+	})
+
+	t.Run("version-not-greater", func(t *testing.T) {
+		if _, err := checkVersion(ReleaseContext{version: "1.0.0", tag: "v1.0.0"}); err == nil {
+			t.Error("expected an error when the version is not greater than the last tag, got nil")
+		}
+		if _, err := checkVersion(ReleaseContext{version: "0.9.0", tag: "v0.9.0"}); err == nil {
+			t.Error("expected an error when the version is lower than the last tag, got nil")
+		}
+	})
+
+	t.Run("failing-tests", func(t *testing.T) {
+		os.WriteFile("main_test.go", []byte("package main\n\nimport \"testing\"\n\nfunc TestFail(t *testing.T) { t.Fail() }\n"), 0644)
+		defer os.Remove("main_test.go")
+
+		if _, err := checkTests(ReleaseContext{}); err == nil {
+			t.Error("expected an error when the tests fail, got nil")
+		}
+	})
+}
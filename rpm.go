@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+)
+
+// the rpm header tags gopher writes, see https://rpm-software-management.github.io/rpm/manual/tags.html
+const (
+	rpmTagHeaderSignatures  = 62
+	rpmTagHeaderImmutable   = 63
+	rpmTagHeaderI18nTable   = 100
+	rpmSigTagSHA1           = 269
+	rpmSigTagSHA256         = 273
+	rpmSigTagSize           = 1000
+	rpmSigTagMD5            = 1004
+	rpmSigTagPayloadSize    = 1007
+	rpmTagName              = 1000
+	rpmTagVersion           = 1001
+	rpmTagRelease           = 1002
+	rpmTagSummary           = 1004
+	rpmTagDescription       = 1005
+	rpmTagBuildTime         = 1006
+	rpmTagBuildHost         = 1007
+	rpmTagSize              = 1009
+	rpmTagLicense           = 1014
+	rpmTagPackager          = 1015
+	rpmTagGroup             = 1016
+	rpmTagURL               = 1020
+	rpmTagOS                = 1021
+	rpmTagArch              = 1022
+	rpmTagFileSizes         = 1028
+	rpmTagFileModes         = 1030
+	rpmTagFileRdevs         = 1033
+	rpmTagFileMtimes        = 1034
+	rpmTagFileDigests       = 1035
+	rpmTagFileLinkTos       = 1036
+	rpmTagFileFlags         = 1037
+	rpmTagFileUserName      = 1039
+	rpmTagFileGroupName     = 1040
+	rpmTagSourceRpm         = 1044
+	rpmTagFileVerifyFlags   = 1045
+	rpmTagProvideName       = 1047
+	rpmTagRequireFlags      = 1048
+	rpmTagRequireName       = 1049
+	rpmTagRequireVersion    = 1050
+	rpmTagFileDevices       = 1095
+	rpmTagFileInodes        = 1096
+	rpmTagFileLangs         = 1097
+	rpmTagProvideFlags      = 1112
+	rpmTagProvideVersion    = 1113
+	rpmTagDirIndexes        = 1116
+	rpmTagBaseNames         = 1117
+	rpmTagDirNames          = 1118
+	rpmTagPayloadFormat     = 1124
+	rpmTagPayloadCompressor = 1125
+	rpmTagPayloadFlags      = 1126
+	rpmTagFileDigestAlgo    = 5011
+)
+
+// the types of rpm header values
+const (
+	rpmInt16       = 3
+	rpmInt32       = 4
+	rpmString      = 6
+	rpmBin         = 7
+	rpmStringArray = 8
+	rpmI18nString  = 9
+)
+
+// dependency and file flags
+const (
+	rpmSenseLess    = 1 << 1
+	rpmSenseEqual   = 1 << 3
+	rpmSenseRpmlib  = 1 << 24
+	rpmFileLicense  = 1 << 7
+	rpmDigestSHA256 = 8
+)
+
+// the rpm release of the packages, bumped when the same version is packaged again
+const rpmRelease = "1"
+
+// the rpm architecture of a target
+func rpmArch(t Target) (string, bool) {
+	switch t.goarch {
+	case "amd64":
+		return "x86_64", true
+	case "386":
+		return "i386", true
+	case "arm64":
+		return "aarch64", true
+	case "arm":
+		return "armv" + t.goarm + "hl", true
+	}
+	return "", false
+}
+
+// rpm packages keep licenses in their own folder
+func rpmLicensePath(name string, file string) string {
+	return "/usr/share/licenses/" + name + "/" + file
+}
+
+// rpm package files are named name-version-release.arch.rpm
+func rpmFileName(p LinuxPackage) string {
+	return p.name + "-" + p.version + "-" + rpmRelease + "." + p.arch + ".rpm"
+}
+
+// one value in an rpm header
+type rpmEntry struct {
+	tag   int32
+	typ   int32
+	count int32
+	data  []byte
+}
+
+// an rpm header, a list of tagged values
+type rpmHeader struct {
+	entries []rpmEntry
+}
+
+func (h *rpmHeader) add(tag int32, typ int32, count int32, data []byte) {
+	h.entries = append(h.entries, rpmEntry{tag, typ, count, data})
+}
+
+func (h *rpmHeader) addString(tag int32, s string) {
+	h.add(tag, rpmString, 1, append([]byte(s), 0))
+}
+
+func (h *rpmHeader) addI18nString(tag int32, s string) {
+	h.add(tag, rpmI18nString, 1, append([]byte(s), 0))
+}
+
+func (h *rpmHeader) addStrings(tag int32, values ...string) {
+	var data []byte
+	for _, s := range values {
+		data = append(append(data, s...), 0)
+	}
+	h.add(tag, rpmStringArray, int32(len(values)), data)
+}
+
+func (h *rpmHeader) addInt32(tag int32, values ...int32) {
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.BigEndian.PutUint32(data[4*i:], uint32(v))
+	}
+	h.add(tag, rpmInt32, int32(len(values)), data)
+}
+
+func (h *rpmHeader) addInt16(tag int32, values ...int16) {
+	data := make([]byte, 2*len(values))
+	for i, v := range values {
+		binary.BigEndian.PutUint16(data[2*i:], uint16(v))
+	}
+	h.add(tag, rpmInt16, int32(len(values)), data)
+}
+
+// the alignment of a value in the data store
+func rpmAlign(typ int32) int {
+	switch typ {
+	case rpmInt16:
+		return 2
+	case rpmInt32:
+		return 4
+	}
+	return 1
+}
+
+// write the header with a region tag wrapping all its entries, the way rpm expects a signed header
+func (h *rpmHeader) bytes(region int32) []byte {
+
+	entries := append([]rpmEntry{}, h.entries...)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].tag < entries[j].tag })
+
+	var index, store bytes.Buffer
+	for _, e := range entries {
+		for store.Len()%rpmAlign(e.typ) != 0 {
+			store.WriteByte(0)
+		}
+		binary.Write(&index, binary.BigEndian, [4]int32{e.tag, e.typ, int32(store.Len()), e.count})
+		store.Write(e.data)
+	}
+
+	// the region entry comes first and points at a trailer at the end of the data
+	count := int32(len(entries) + 1)
+	trailer := int32(store.Len())
+	binary.Write(&store, binary.BigEndian, [4]int32{region, rpmBin, -count * 16, 16})
+
+	var b bytes.Buffer
+	b.Write([]byte{0x8e, 0xad, 0xe8, 0x01, 0, 0, 0, 0})
+	binary.Write(&b, binary.BigEndian, [2]int32{count, int32(store.Len())})
+	binary.Write(&b, binary.BigEndian, [4]int32{region, rpmBin, trailer, 16})
+	b.Write(index.Bytes())
+	b.Write(store.Bytes())
+	return b.Bytes()
+}
+
+// write one entry of a cpio archive in the newc format rpm payloads use
+func writeCpioEntry(b *bytes.Buffer, name string, ino int, mode int64, mtime int64, data []byte) {
+	fmt.Fprintf(b, "070701%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x",
+		ino, mode, 0, 0, 1, mtime, len(data), 0, 0, 0, 0, len(name)+1, 0)
+	b.WriteString(name)
+	b.WriteByte(0)
+	for b.Len()%4 != 0 {
+		b.WriteByte(0)
+	}
+	b.Write(data)
+	for b.Len()%4 != 0 {
+		b.WriteByte(0)
+	}
+}
+
+// build an .rpm package, see https://rpm-software-management.github.io/rpm/manual/format.html
+func buildRpm(p LinuxPackage) ([]byte, error) {
+
+	mtime := int32(p.mtime.Unix())
+
+	// the payload is a gzipped cpio archive of the files
+	var cpio bytes.Buffer
+	var size int32
+	var dirs []string
+	var dir_indexes, sizes, mtimes, flags, inodes, devices, verify []int32
+	var modes, rdevs []int16
+	var basenames, digests, linktos, users, groups, langs []string
+
+	for i, f := range p.files {
+		mode := 0100000 | f.mode
+		writeCpioEntry(&cpio, "."+f.path, i+1, mode, int64(mtime), f.data)
+
+		dir, base := path.Split(f.path)
+		index := -1
+		for j, d := range dirs {
+			if d == dir {
+				index = j
+			}
+		}
+		if index == -1 {
+			dirs = append(dirs, dir)
+			index = len(dirs) - 1
+		}
+
+		file_flags := int32(0)
+		if f.license {
+			file_flags = rpmFileLicense
+		}
+
+		size += int32(len(f.data))
+		dir_indexes = append(dir_indexes, int32(index))
+		basenames = append(basenames, base)
+		sizes = append(sizes, int32(len(f.data)))
+		modes = append(modes, int16(mode))
+		rdevs = append(rdevs, 0)
+		mtimes = append(mtimes, mtime)
+		digests = append(digests, fmt.Sprintf("%x", sha256.Sum256(f.data)))
+		linktos = append(linktos, "")
+		flags = append(flags, file_flags)
+		users = append(users, "root")
+		groups = append(groups, "root")
+		devices = append(devices, 1)
+		inodes = append(inodes, int32(i+1))
+		langs = append(langs, "")
+		verify = append(verify, -1)
+	}
+	writeCpioEntry(&cpio, "TRAILER!!!", 0, 0, 0, nil)
+
+	var payload bytes.Buffer
+	gw, _ := gzip.NewWriterLevel(&payload, gzip.BestCompression)
+	if _, err := gw.Write(cpio.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+
+	host, _ := os.Hostname()
+	version := p.version + "-" + rpmRelease
+
+	var h rpmHeader
+	h.addStrings(rpmTagHeaderI18nTable, "C")
+	h.addString(rpmTagName, p.name)
+	h.addString(rpmTagVersion, p.version)
+	h.addString(rpmTagRelease, rpmRelease)
+	h.addI18nString(rpmTagSummary, p.description)
+	h.addI18nString(rpmTagDescription, p.description)
+	h.addInt32(rpmTagBuildTime, mtime)
+	h.addString(rpmTagBuildHost, host)
+	h.addInt32(rpmTagSize, size)
+	h.addString(rpmTagLicense, p.license)
+	h.addString(rpmTagPackager, p.maintainer)
+	h.addI18nString(rpmTagGroup, "Unspecified")
+	if p.homepage != "" {
+		h.addString(rpmTagURL, p.homepage)
+	}
+	h.addString(rpmTagOS, "linux")
+	h.addString(rpmTagArch, p.arch)
+	h.addInt32(rpmTagFileSizes, sizes...)
+	h.addInt16(rpmTagFileModes, modes...)
+	h.addInt16(rpmTagFileRdevs, rdevs...)
+	h.addInt32(rpmTagFileMtimes, mtimes...)
+	h.addStrings(rpmTagFileDigests, digests...)
+	h.addStrings(rpmTagFileLinkTos, linktos...)
+	h.addInt32(rpmTagFileFlags, flags...)
+	h.addStrings(rpmTagFileUserName, users...)
+	h.addStrings(rpmTagFileGroupName, groups...)
+	// rpm tells binary packages from source packages by this tag
+	h.addString(rpmTagSourceRpm, p.name+"-"+version+".src.rpm")
+	h.addInt32(rpmTagFileVerifyFlags, verify...)
+	h.addStrings(rpmTagProvideName, p.name)
+	h.addInt32(rpmTagProvideFlags, rpmSenseEqual)
+	h.addStrings(rpmTagProvideVersion, version)
+	// the sha256 file digests need rpm 4.6, older versions would read them as md5
+	var rpmlib int32 = rpmSenseRpmlib | rpmSenseLess | rpmSenseEqual
+	h.addStrings(rpmTagRequireName, "rpmlib(CompressedFileNames)", "rpmlib(FileDigests)", "rpmlib(PayloadFilesHavePrefix)")
+	h.addInt32(rpmTagRequireFlags, rpmlib, rpmlib, rpmlib)
+	h.addStrings(rpmTagRequireVersion, "3.0.4-1", "4.6.0-1", "4.0-1")
+	h.addInt32(rpmTagFileDevices, devices...)
+	h.addInt32(rpmTagFileInodes, inodes...)
+	h.addStrings(rpmTagFileLangs, langs...)
+	h.addInt32(rpmTagDirIndexes, dir_indexes...)
+	h.addStrings(rpmTagBaseNames, basenames...)
+	h.addStrings(rpmTagDirNames, dirs...)
+	h.addString(rpmTagPayloadFormat, "cpio")
+	h.addString(rpmTagPayloadCompressor, "gzip")
+	h.addString(rpmTagPayloadFlags, "9")
+	h.addInt32(rpmTagFileDigestAlgo, rpmDigestSHA256)
+	header := h.bytes(rpmTagHeaderImmutable)
+
+	// the signature covers the header and the payload
+	signed := append(append([]byte{}, header...), payload.Bytes()...)
+	var sig rpmHeader
+	sig.addString(rpmSigTagSHA1, fmt.Sprintf("%x", sha1.Sum(header)))
+	sig.addString(rpmSigTagSHA256, fmt.Sprintf("%x", sha256.Sum256(header)))
+	sig.addInt32(rpmSigTagSize, int32(len(signed)))
+	md5sum := md5.Sum(signed)
+	sig.add(rpmSigTagMD5, rpmBin, 16, md5sum[:])
+	sig.addInt32(rpmSigTagPayloadSize, int32(cpio.Len()))
+	signature := sig.bytes(rpmTagHeaderSignatures)
+
+	var b bytes.Buffer
+
+	// the lead is a fixed block rpm only checks the magic and version of
+	lead := make([]byte, 96)
+	copy(lead, []byte{0xed, 0xab, 0xee, 0xdb, 3, 0})
+	copy(lead[10:75], p.name+"-"+version)
+	binary.BigEndian.PutUint16(lead[76:], 1) // linux
+	binary.BigEndian.PutUint16(lead[78:], 5) // header style signature
+	b.Write(lead)
+
+	b.Write(signature)
+	// the main header starts on an 8 byte boundary
+	for b.Len()%8 != 0 {
+		b.WriteByte(0)
+	}
+	b.Write(header)
+	b.Write(payload.Bytes())
+	return b.Bytes(), nil
+}
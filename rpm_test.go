@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRpmArch(t *testing.T) {
+	testCases := []struct {
+		target   Target
+		expected string
+	}{
+		{Target{goos: "linux", goarch: "amd64"}, "x86_64"},
+		{Target{goos: "linux", goarch: "386"}, "i386"},
+		{Target{goos: "linux", goarch: "arm64"}, "aarch64"},
+		{Target{goos: "linux", goarch: "arm", goarm: "6"}, "armv6hl"},
+		{Target{goos: "linux", goarch: "arm", goarm: "7"}, "armv7hl"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.target.String(), func(t *testing.T) {
+			if got, _ := rpmArch(tc.target); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuildRpm(t *testing.T) {
+
+	pkg := LinuxPackage{
+		name:        "tool",
+		version:     "2.0.0~rc1",
+		arch:        "x86_64",
+		maintainer:  "Jane Doe <jane@example.com>",
+		homepage:    "https://github.com/user/tool",
+		license:     "MIT",
+		description: "A tool that does things.",
+		mtime:       time.Unix(1700000000, 0),
+		files: []PackageFile{
+			{path: "/usr/bin/tool", mode: 0755, data: []byte("binary")},
+			{path: rpmLicensePath("tool", "LICENSE"), mode: 0644, data: []byte("MIT License\n"), license: true},
+		},
+	}
+
+	if got := rpmFileName(pkg); got != "tool-2.0.0~rc1-1.x86_64.rpm" {
+		t.Errorf("unexpected file name %q", got)
+	}
+
+	content, err := buildRpm(pkg)
+	if err != nil {
+		t.Fatalf("buildRpm() failed: %v", err)
+	}
+
+	if !bytes.HasPrefix(content, []byte{0xed, 0xab, 0xee, 0xdb, 3, 0}) {
+		t.Fatalf("expected the rpm lead, got %x", content[:6])
+	}
+
+	signature, end := readRpmHeader(t, content, 96)
+	// the main header starts on an 8 byte boundary
+	end = (end + 7) / 8 * 8
+	header, payload := readRpmHeader(t, content, end)
+
+	signed := content[end:]
+	sum := md5.Sum(signed)
+	if !bytes.Equal(signature[rpmSigTagMD5], sum[:]) {
+		t.Errorf("the md5 signature does not match the header and payload")
+	}
+	if got := binary.BigEndian.Uint32(signature[rpmSigTagSize]); int(got) != len(signed) {
+		t.Errorf("expected the signed size %d, got %d", len(signed), got)
+	}
+
+	strs := func(tag int32) string { return strings.Trim(string(header[tag]), "\x00") }
+	expected := map[int32]string{
+		rpmTagName:      "tool",
+		rpmTagVersion:   "2.0.0~rc1",
+		rpmTagRelease:   "1",
+		rpmTagArch:      "x86_64",
+		rpmTagLicense:   "MIT",
+		rpmTagPackager:  "Jane Doe <jane@example.com>",
+		rpmTagURL:       "https://github.com/user/tool",
+		rpmTagSourceRpm: "tool-2.0.0~rc1-1.src.rpm",
+		rpmTagBaseNames: "tool\x00LICENSE",
+		rpmTagDirNames:  "/usr/bin/\x00/usr/share/licenses/tool/",
+		// the file digests are sha256, which needs rpmlib(FileDigests)
+		rpmTagRequireName:    "rpmlib(CompressedFileNames)\x00rpmlib(FileDigests)\x00rpmlib(PayloadFilesHavePrefix)",
+		rpmTagRequireVersion: "3.0.4-1\x004.6.0-1\x004.0-1",
+	}
+	for tag, want := range expected {
+		if got := strs(tag); got != want {
+			t.Errorf("expected tag %d to be %q, got %q", tag, want, got)
+		}
+	}
+
+	flags := header[rpmTagRequireFlags]
+	if len(flags) != 3*4 {
+		t.Fatalf("expected a flag for each of the 3 requirements, got %d bytes", len(flags))
+	}
+	for i := 0; i < 3; i++ {
+		if got := int32(binary.BigEndian.Uint32(flags[i*4:])); got != rpmSenseRpmlib|rpmSenseLess|rpmSenseEqual {
+			t.Errorf("expected requirement %d to be an rpmlib <= requirement, got flags %#x", i, got)
+		}
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(content[payload:]))
+	if err != nil {
+		t.Fatalf("failed to read the payload: %v", err)
+	}
+	cpio, _ := io.ReadAll(gr)
+	if !bytes.HasPrefix(cpio, []byte("070701")) || !bytes.Contains(cpio, []byte("./usr/bin/tool\x00")) || !bytes.Contains(cpio, []byte("binary")) {
+		t.Errorf("expected the binary in the cpio payload, got %q", cpio)
+	}
+	if !bytes.Contains(cpio, []byte("TRAILER!!!")) {
+		t.Errorf("expected the cpio trailer in the payload")
+	}
+}
+
+// read an rpm header at the offset, checking the region that wraps it
+// returns the raw data of every tag and the offset after the header
+func readRpmHeader(t *testing.T, content []byte, offset int) (map[int32][]byte, int) {
+
+	if !bytes.HasPrefix(content[offset:], []byte{0x8e, 0xad, 0xe8, 0x01}) {
+		t.Fatalf("expected a header at %d", offset)
+	}
+	count := int(binary.BigEndian.Uint32(content[offset+8:]))
+	size := int(binary.BigEndian.Uint32(content[offset+12:]))
+	index := content[offset+16 : offset+16+count*16]
+	store := content[offset+16+count*16 : offset+16+count*16+size]
+
+	entry := func(b []byte) (int32, int32, int32, int32) {
+		return int32(binary.BigEndian.Uint32(b)), int32(binary.BigEndian.Uint32(b[4:])), int32(binary.BigEndian.Uint32(b[8:])), int32(binary.BigEndian.Uint32(b[12:]))
+	}
+
+	// the first entry is the region, its trailer points back over the whole index
+	region, _, trailer, _ := entry(index)
+	tag, _, back, _ := entry(store[trailer:])
+	if tag != region || int(-back) != count*16 {
+		t.Fatalf("invalid region trailer: tag %d, offset %d", tag, back)
+	}
+
+	tags := map[int32][]byte{}
+	for i := 1; i < count; i++ {
+		tag, typ, off, n := entry(index[i*16:])
+		var length int
+		switch typ {
+		case rpmInt16:
+			length = 2 * int(n)
+		case rpmInt32:
+			length = 4 * int(n)
+		case rpmBin:
+			length = int(n)
+		default:
+			// strings run up to their last nul
+			length = 0
+			for seen := int32(0); seen < n; length++ {
+				if store[int(off)+length] == 0 {
+					seen++
+				}
+			}
+		}
+		if off%int32(rpmAlign(typ)) != 0 {
+			t.Errorf("tag %d is not aligned", tag)
+		}
+		tags[tag] = store[off : int(off)+length]
+	}
+	return tags, offset + 16 + count*16 + size
+}
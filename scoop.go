@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// a scoop manifest, the fields are in the order scoop lists them
+type ScoopManifest struct {
+	Version      string                       `json:"version"`
+	Description  string                       `json:"description"`
+	Homepage     string                       `json:"homepage"`
+	License      string                       `json:"license"`
+	Architecture map[string]ScoopArchitecture `json:"architecture"`
+	Bin          string                       `json:"bin"`
+	Checkver     interface{}                  `json:"checkver"`
+	Autoupdate   ScoopAutoupdate              `json:"autoupdate"`
+}
+
+// the download of one architecture in a scoop manifest
+type ScoopArchitecture struct {
+	URL  string `json:"url"`
+	Hash string `json:"hash,omitempty"`
+}
+
+// tells scoop how to build the next version of the manifest
+// $version is replaced with the version checkver found
+type ScoopAutoupdate struct {
+	Architecture map[string]ScoopArchitecture `json:"architecture"`
+	Hash         ScoopHash                    `json:"hash"`
+}
+
+// where scoop finds the hashes of a new version
+// $baseurl is the download url without the file name, $basename the file name
+type ScoopHash struct {
+	URL   string `json:"url"`
+	Regex string `json:"regex"`
+}
+
+// the scoop architectures and the arch part of the windows archive names built for them
+var scoopArchitectures = []struct {
+	scoop   string
+	archive string
+}{
+	{"64bit", "x86_64"},
+	{"32bit", "i386"},
+	{"arm64", "arm64"},
+}
+
+// name of the windows archive built for a scoop architecture
+func scoopArchiveName(name string, version string, arch string) string {
+	return name + "_" + version + "_Windows_" + arch + ".zip"
+}
+
+// build the autoupdate block for the architectures in the manifest
+// the urls follow the archive naming, the hashes come from the goreleaser checksums file
+func scoopAutoupdate(forge Forge, owner string, name string, archives map[string]string) ScoopAutoupdate {
+
+	auto := ScoopAutoupdate{
+		Architecture: map[string]ScoopArchitecture{},
+		Hash: ScoopHash{
+			URL:   "$baseurl/" + checksumsName(name, "$version"),
+			Regex: "$sha256\\s+$basename",
+		},
+	}
+	for _, a := range scoopArchitectures {
+		if _, ok := archives[a.scoop]; ok {
+			file := scoopArchiveName(name, "$version", a.archive)
+			auto.Architecture[a.scoop] = ScoopArchitecture{URL: forge.releaseURL(owner, name, "$version", file)}
+		}
+	}
+	return auto
+}
+
+// find the windows archives of this version, in dist or in the checksums file
+// returns the archive names by scoop architecture
+func findScoopArchives(name string, version string, sums map[string]string) map[string]string {
+
+	archives := map[string]string{}
+	for _, a := range scoopArchitectures {
+		archive := scoopArchiveName(name, version, a.archive)
+		_, listed := sums[archive]
+		_, err := os.Stat(filepath.Join(distDir, archive))
+		if listed || err == nil {
+			archives[a.scoop] = archive
+		}
+	}
+	return archives
+}
+
+// encode the manifest the way scoop buckets format them
+func encodeScoopManifest(m ScoopManifest) ([]byte, error) {
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetIndent("", "    ")
+	// urls have & in query strings and scoop doesn't need them escaped
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(m); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// find the folder of a bucket the manifests go in
+// buckets keep them in bucket/ or at the root
+func getBucketDir(bucket string) string {
+	if info, err := os.Stat(filepath.Join(bucket, "bucket")); err == nil && info.IsDir() {
+		return filepath.Join(bucket, "bucket")
+	}
+	return bucket
+}
+
+// copy the manifest into a local clone of a scoop bucket, show what changed and commit it
+func publishScoopManifest(manifest string, bucket string, name string, version string) error {
+
+	color.Cyan("Publishing the manifest to the bucket in " + bucket + "...")
+
+	if out, err := exec.Command("git", "-C", bucket, "rev-parse", "--is-inside-work-tree").Output(); err != nil || strings.TrimSpace(string(out)) != "true" {
+		fmt.Print("💥 ")
+		color.Red(bucket + " is not a git repository. Point --bucket or the scoop.bucket setting at a clone of your bucket.")
+		return fmt.Errorf("%s is not a git repository", bucket)
+	}
+
+	content, err := os.ReadFile(manifest)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return err
+	}
+
+	dest := filepath.Join(getBucketDir(bucket), name+".json")
+	rel, _ := filepath.Rel(bucket, dest)
+	rel = filepath.ToSlash(rel)
+
+	_, exists := os.Stat(dest)
+	err = os.WriteFile(dest, content, 0644)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error writing " + dest)
+		color.Red(err.Error())
+		return err
+	}
+
+	status, err := exec.Command("git", "-C", bucket, "status", "--porcelain", "--", rel).Output()
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return err
+	}
+	if strings.TrimSpace(string(status)) == "" {
+		color.Green("✔  " + rel + " is already up to date in the bucket.")
+		return nil
+	}
+
+	// show what changed against the entry in the bucket
+	if exists == nil {
+		color.White("💬  Changes to " + rel + ":")
+		cmd := exec.Command("git", "-C", bucket, "--no-pager", "diff", "--", rel)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.Run()
+	} else {
+		color.White("💬  " + rel + " is a new entry in the bucket.")
+	}
+
+	message := name + ": update to " + version
+	color.Cyan("Committing " + rel + "...")
+	for _, args := range [][]string{
+		{"-C", bucket, "add", "--", rel},
+		{"-C", bucket, "commit", "-q", "-m", message, "--", rel},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			fmt.Print("💥 ")
+			color.Red("Error committing to the bucket")
+			color.Red(err.Error())
+			return err
+		}
+	}
+
+	color.Green("✔  Committed \"" + message + "\" to the bucket. Push it when you are ready.")
+	return nil
+}
+
+// generate a scoop manifest file, and publish it to a bucket when one is set
+func generateScoopFile(opts ScoopOptions) error {
+
+	color.Cyan("Generating scoop manifest file...")
+
+	info, err := getReleaseInfo()
+	if err != nil {
+		return err
+	}
+	name, username, version, forge := info.name, info.owner, info.version, info.forge
+
+	manifest := ScoopManifest{
+		Version:      version,
+		Bin:          name + ".exe",
+		Architecture: map[string]ScoopArchitecture{},
+	}
+
+	color.Cyan("Adding generic description, you can edit it later...")
+	manifest.Description = "A new scoop package"
+
+	manifest.Homepage = info.homepage
+	manifest.Checkver = forge.checkver(username, name)
+
+	manifest.License = info.license
+	if manifest.License == "" {
+		color.Yellow("⚠  No LICENSE file found, the manifest will say freeware.")
+		manifest.License = "freeware"
+	}
+
+	errors := 0
+
+	checksum_file := filepath.Join(distDir, checksumsName(name, version))
+	color.Cyan("Reading the checksums from " + checksum_file + "...")
+	sums, err := readChecksums(checksum_file)
+	if err != nil {
+		color.Yellow("⚠  Could not read the checksum file: " + checksum_file)
+		color.White("💬  Make sure you have built the windows binary using gopher release command.")
+		errors++
+	}
+
+	color.Cyan("Checking for for existing windows binary releases in dist/ folder...")
+	archives := findScoopArchives(name, version, sums)
+	if len(archives) == 0 {
+		// point at the usual 64 bit archive so the manifest can be fixed by hand
+		color.Yellow("⚠  Could not find any windows archives for version " + version + ".")
+		color.White("💬  Make sure you have built the windows binary using gopher release command.")
+		archives["64bit"] = scoopArchiveName(name, version, "x86_64")
+		errors++
+	}
+
+	for _, a := range scoopArchitectures {
+		archive, ok := archives[a.scoop]
+		if !ok {
+			continue
+		}
+
+		arch := ScoopArchitecture{URL: info.downloadURL(archive)}
+		color.Blue("🆗 " + a.scoop + " download url: " + arch.URL)
+
+		if hash, ok := sums[archive]; ok {
+			arch.Hash = hash
+			color.Blue("🆗 Found a checksum for " + archive + " -> " + hash)
+		} else if sums != nil {
+			color.Yellow("⚠  Could not find a checksum for " + archive + " in the checksum file.")
+			color.White("💬  Make sure you have built the windows binary using gopher release command.")
+			errors++
+		}
+
+		manifest.Architecture[a.scoop] = arch
+	}
+
+	color.Cyan("Adding the autoupdate rules...")
+	manifest.Autoupdate = scoopAutoupdate(forge, username, name, archives)
+
+	color.Cyan("Creating the scoop manifest...")
+	content, ej := encodeScoopManifest(manifest)
+	if ej != nil {
+		fmt.Print("💥 ")
+		color.Red(ej.Error())
+		return ej
+	}
+	color.Blue("🆗 Manifest created successfully.")
+
+	scoopfile_path := filepath.Join(distDir, name+".json")
+
+	// write the manifest to the file
+	color.Cyan("Creating " + scoopfile_path)
+	err = os.WriteFile(scoopfile_path, content, 0644)
+	if err != nil {
+		color.Red("Error creating " + scoopfile_path)
+		color.Red(err.Error())
+		return err
+	}
+	color.Blue("🆗 scoop file has been written to disk.")
+
+	bucket := getSettingValue("scoop.bucket", opts.bucket)
+
+	if errors > 0 {
+		color.Green("⚠  Scoop manifest file " + name + ".json created with some warnings.")
+		if bucket != "" {
+			color.Yellow("⚠  The manifest was not published to the bucket, fix the warnings first.")
+		}
+		return fmt.Errorf("scoop manifest creation completed with %d warnings", errors)
+	}
+
+	color.Green("✔  Scoop manifest file " + name + ".json created successfully.")
+
+	if bucket == "" {
+		return nil
+	}
+	return publishScoopManifest(scoopfile_path, bucket, name, version)
+}
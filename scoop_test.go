@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestFindScoopArchives(t *testing.T) {
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	os.Mkdir(distDir, 0755)
+	os.WriteFile(filepath.Join(distDir, "tool_1.0.0_Windows_arm64.zip"), []byte("zip"), 0644)
+	os.WriteFile(filepath.Join(distDir, "tool_0.9.0_Windows_i386.zip"), []byte("old"), 0644)
+
+	sums := map[string]string{
+		"tool_1.0.0_Windows_x86_64.zip":  "aaa",
+		"tool_1.0.0_Linux_x86_64.tar.gz": "bbb",
+	}
+
+	archives := findScoopArchives("tool", "1.0.0", sums)
+	expected := map[string]string{
+		"64bit": "tool_1.0.0_Windows_x86_64.zip",
+		"arm64": "tool_1.0.0_Windows_arm64.zip",
+	}
+	if len(archives) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, archives)
+	}
+	for arch, archive := range expected {
+		if archives[arch] != archive {
+			t.Errorf("expected %s to be %q, got %q", arch, archive, archives[arch])
+		}
+	}
+}
+
+func TestGenerateScoopFileArchitectures(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	os.Mkdir(distDir, 0755)
+	os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+	os.WriteFile("main.go", []byte("package main\nconst version = \"2.0.0\""), 0644)
+	checksums := "111  tool_2.0.0_Windows_x86_64.zip\n" +
+		"222  tool_2.0.0_Windows_i386.zip\n" +
+		"333  tool_2.0.0_Windows_arm64.zip\n" +
+		"444  tool_2.0.0_Linux_x86_64.tar.gz\n"
+	os.WriteFile(filepath.Join(distDir, "tool_2.0.0_checksums.txt"), []byte(checksums), 0644)
+
+	if err := generateScoopFile(ScoopOptions{}); err != nil {
+		t.Fatalf("generateScoopFile() failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(distDir, "tool.json"))
+	var manifest ScoopManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		t.Fatalf("the manifest is not valid json: %v\n%s", err, content)
+	}
+
+	expected := map[string]ScoopArchitecture{
+		"64bit": {URL: "https://github.com/user/tool/releases/download/v2.0.0/tool_2.0.0_Windows_x86_64.zip", Hash: "111"},
+		"32bit": {URL: "https://github.com/user/tool/releases/download/v2.0.0/tool_2.0.0_Windows_i386.zip", Hash: "222"},
+		"arm64": {URL: "https://github.com/user/tool/releases/download/v2.0.0/tool_2.0.0_Windows_arm64.zip", Hash: "333"},
+	}
+	if len(manifest.Architecture) != len(expected) {
+		t.Fatalf("expected %d architectures, got %v", len(expected), manifest.Architecture)
+	}
+	for arch, want := range expected {
+		if got := manifest.Architecture[arch]; got != want {
+			t.Errorf("expected %s to be %+v, got %+v", arch, want, got)
+		}
+	}
+	if manifest.Bin != "tool.exe" || manifest.Version != "2.0.0" {
+		t.Errorf("unexpected bin or version: %q, %q", manifest.Bin, manifest.Version)
+	}
+
+	t.Run("missing-hash", func(t *testing.T) {
+		os.WriteFile(filepath.Join(distDir, "tool_2.0.0_checksums.txt"), []byte("111  tool_2.0.0_Windows_x86_64.zip\n"), 0644)
+		os.WriteFile(filepath.Join(distDir, "tool_2.0.0_Windows_arm64.zip"), []byte("zip"), 0644)
+
+		if err := generateScoopFile(ScoopOptions{}); err == nil {
+			t.Error("expected a warning for the archive without a checksum, got nil")
+		}
+		content, _ := os.ReadFile(filepath.Join(distDir, "tool.json"))
+		var manifest ScoopManifest
+		json.Unmarshal(content, &manifest)
+		if manifest.Architecture["arm64"].Hash != "" || manifest.Architecture["64bit"].Hash != "111" {
+			t.Errorf("expected only the 64bit archive to have a hash, got %+v", manifest.Architecture)
+		}
+	})
+}
+
+func TestScoopCheckver(t *testing.T) {
+
+	testCases := []struct {
+		tag     string
+		version string
+	}{
+		{"v1.2.0", "1.2.0"},
+		{"v1.2.0-rc.1", "1.2.0-rc.1"},
+		{"v2.0.0-beta-2", "2.0.0-beta-2"},
+	}
+
+	for _, host := range []string{"gitlab.com", "git.example.com"} {
+		checkver, ok := getForge(host).checkver("user", "tool").(map[string]string)
+		if !ok {
+			t.Fatalf("expected a checkver rule for %s, got %v", host, getForge(host).checkver("user", "tool"))
+		}
+		re := regexp.MustCompile(checkver["regex"])
+		for _, tc := range testCases {
+			t.Run(host+"/"+tc.tag, func(t *testing.T) {
+				m := re.FindStringSubmatch(tc.tag)
+				if m == nil || m[1] != tc.version {
+					t.Errorf("expected %q to give version %q, got %v", tc.tag, tc.version, m)
+				}
+			})
+		}
+	}
+}
+
+func TestScoopAutoupdate(t *testing.T) {
+
+	t.Setenv("GOPHER_FORGE", "")
+	archives := map[string]string{"64bit": "tool_1.0.0_Windows_x86_64.zip", "arm64": "tool_1.0.0_Windows_arm64.zip"}
+
+	testCases := []struct {
+		host  string
+		url64 string
+	}{
+		{"github.com", "https://github.com/user/tool/releases/download/v$version/tool_$version_Windows_x86_64.zip"},
+		{"gitlab.com", "https://gitlab.com/user/tool/-/releases/v$version/downloads/tool_$version_Windows_x86_64.zip"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.host, func(t *testing.T) {
+			auto := scoopAutoupdate(getForge(tc.host), "user", "tool", archives)
+
+			if len(auto.Architecture) != 2 {
+				t.Fatalf("expected an entry per architecture, got %v", auto.Architecture)
+			}
+			if got := auto.Architecture["64bit"].URL; got != tc.url64 {
+				t.Errorf("expected %q, got %q", tc.url64, got)
+			}
+			if auto.Architecture["64bit"].Hash != "" {
+				t.Error("expected the autoupdate urls to have no hash")
+			}
+			if auto.Hash.URL != "$baseurl/tool_$version_checksums.txt" {
+				t.Errorf("expected the hash to come from the checksums file, got %q", auto.Hash.URL)
+			}
+		})
+	}
+}
+
+func TestScoopBucket(t *testing.T) {
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	git := setupTestRepo(t)
+
+	os.Mkdir(distDir, 0755)
+	os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+	os.WriteFile("main.go", []byte("package main\nconst version = \"1.1.0\""), 0644)
+	os.WriteFile(filepath.Join(distDir, "tool_1.1.0_checksums.txt"), []byte("111  tool_1.1.0_Windows_x86_64.zip\n"), 0644)
+
+	// a bucket with the previous version of the manifest
+	bucket := t.TempDir()
+	os.Mkdir(filepath.Join(bucket, "bucket"), 0755)
+	os.WriteFile(filepath.Join(bucket, "bucket", "tool.json"), []byte("{\n    \"version\": \"1.0.0\"\n}\n"), 0644)
+	git("-C", bucket, "init", "-q", "-b", "master")
+	git("-C", bucket, "add", "-A")
+	git("-C", bucket, "commit", "-q", "-m", "tool: add version 1.0.0")
+
+	lastCommit := func() string {
+		out, _ := exec.Command("git", "-C", bucket, "log", "-1", "--format=%s").Output()
+		return strings.TrimSpace(string(out))
+	}
+
+	t.Run("update", func(t *testing.T) {
+		if err := generateScoopFile(ScoopOptions{bucket: bucket}); err != nil {
+			t.Fatalf("generateScoopFile() failed: %v\n%s", err, buff.String())
+		}
+
+		published, _ := os.ReadFile(filepath.Join(bucket, "bucket", "tool.json"))
+		built, _ := os.ReadFile(filepath.Join(distDir, "tool.json"))
+		if string(published) != string(built) {
+			t.Errorf("expected the bucket entry to match the manifest, got %q", string(published))
+		}
+		if got := lastCommit(); got != "tool: update to 1.1.0" {
+			t.Errorf("expected the update to be committed, got %q", got)
+		}
+	})
+
+	t.Run("up-to-date", func(t *testing.T) {
+		buff.Reset()
+		if err := generateScoopFile(ScoopOptions{bucket: bucket}); err != nil {
+			t.Fatalf("generateScoopFile() failed: %v", err)
+		}
+		if !strings.Contains(buff.String(), "already up to date") {
+			t.Errorf("expected nothing to commit, got %q", buff.String())
+		}
+		out, _ := exec.Command("git", "-C", bucket, "rev-list", "--count", "HEAD").Output()
+		if strings.TrimSpace(string(out)) != "2" {
+			t.Errorf("expected no new commit, got %s commits", strings.TrimSpace(string(out)))
+		}
+	})
+
+	t.Run("root-bucket-from-setting", func(t *testing.T) {
+		root := t.TempDir()
+		git("-C", root, "init", "-q")
+		os.WriteFile(projectConfigFile, []byte("[scoop]\nbucket = '"+root+"'\n"), 0644)
+		defer os.Remove(projectConfigFile)
+
+		if err := generateScoopFile(ScoopOptions{}); err != nil {
+			t.Fatalf("generateScoopFile() failed: %v\n%s", err, buff.String())
+		}
+		if _, err := os.Stat(filepath.Join(root, "tool.json")); err != nil {
+			t.Errorf("expected the manifest at the root of the bucket: %v", err)
+		}
+	})
+
+	t.Run("not-a-repo", func(t *testing.T) {
+		if err := generateScoopFile(ScoopOptions{bucket: t.TempDir()}); err == nil {
+			t.Error("expected an error for a bucket that is not a git repository, got nil")
+		}
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		os.Remove(filepath.Join(distDir, "tool_1.1.0_checksums.txt"))
+		os.WriteFile("main.go", []byte("package main\nconst version = \"1.2.0\""), 0644)
+		generateScoopFile(ScoopOptions{bucket: bucket})
+		if got := lastCommit(); got != "tool: update to 1.1.0" {
+			t.Errorf("expected a manifest with warnings not to be published, got %q", got)
+		}
+	})
+}
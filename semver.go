@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// a version number as described by https://semver.org/spec/v2.0.0.html
+type SemVer struct {
+	major int
+	minor int
+	patch int
+	pre   []string
+	build []string
+}
+
+// check that a dot separated identifier list only uses [0-9A-Za-z-]
+func validIdentifiers(ids []string, numeric bool) error {
+	for _, id := range ids {
+		if id == "" {
+			return fmt.Errorf("empty identifier")
+		}
+		for _, c := range id {
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-') {
+				return fmt.Errorf("invalid character %q in %q", c, id)
+			}
+		}
+		if numeric && isNumeric(id) && len(id) > 1 && id[0] == '0' {
+			return fmt.Errorf("numeric identifier %q has a leading zero", id)
+		}
+	}
+	return nil
+}
+
+// check if an identifier only contains digits
+func isNumeric(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, c := range id {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parse a version like 1.2.3, 1.2.3-rc.1 or v1.2.3+build.5
+func parseSemVer(s string) (SemVer, error) {
+
+	var v SemVer
+	rest := strings.TrimPrefix(s, "v")
+
+	if before, build, ok := strings.Cut(rest, "+"); ok {
+		v.build = strings.Split(build, ".")
+		if err := validIdentifiers(v.build, false); err != nil {
+			return SemVer{}, fmt.Errorf("invalid build metadata in version %q: %w", s, err)
+		}
+		rest = before
+	}
+
+	if before, pre, ok := strings.Cut(rest, "-"); ok {
+		v.pre = strings.Split(pre, ".")
+		if err := validIdentifiers(v.pre, true); err != nil {
+			return SemVer{}, fmt.Errorf("invalid prerelease in version %q: %w", s, err)
+		}
+		rest = before
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return SemVer{}, fmt.Errorf("invalid version %q: expected MAJOR.MINOR.PATCH", s)
+	}
+
+	numbers := make([]int, 3)
+	for i, p := range parts {
+		if !isNumeric(p) || len(p) > 1 && p[0] == '0' {
+			return SemVer{}, fmt.Errorf("invalid version %q: %q is not a valid number", s, p)
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return SemVer{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		numbers[i] = n
+	}
+	v.major, v.minor, v.patch = numbers[0], numbers[1], numbers[2]
+
+	return v, nil
+}
+
+// the version in its canonical form, without a leading v
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	if len(v.pre) > 0 {
+		s += "-" + strings.Join(v.pre, ".")
+	}
+	if len(v.build) > 0 {
+		s += "+" + strings.Join(v.build, ".")
+	}
+	return s
+}
+
+// compare two prerelease identifiers following the semver precedence rules
+func compareIdentifier(a string, b string) int {
+	an, bn := isNumeric(a), isNumeric(b)
+	switch {
+	case an && bn:
+		// compare by length first so huge numbers don't overflow
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	case an:
+		return -1
+	case bn:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// compare the precedence of two versions, build metadata is ignored
+// returns -1, 0 or 1 like strings.Compare
+func compareSemVer(a SemVer, b SemVer) int {
+
+	for _, d := range []int{a.major - b.major, a.minor - b.minor, a.patch - b.patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+
+	// a version without a prerelease is higher than one with it
+	switch {
+	case len(a.pre) == 0 && len(b.pre) == 0:
+		return 0
+	case len(a.pre) == 0:
+		return 1
+	case len(b.pre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a.pre) && i < len(b.pre); i++ {
+		if c := compareIdentifier(a.pre[i], b.pre[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(a.pre) < len(b.pre):
+		return -1
+	case len(a.pre) > len(b.pre):
+		return 1
+	}
+	return 0
+}
+
+// increment the major, minor or patch number of a release version
+func (v SemVer) incLevel(level string) (SemVer, error) {
+	r := SemVer{major: v.major, minor: v.minor, patch: v.patch}
+	switch level {
+	case "major":
+		r.major, r.minor, r.patch = r.major+1, 0, 0
+	case "minor":
+		r.minor, r.patch = r.minor+1, 0
+	case "patch", "build", "":
+		r.patch++
+	default:
+		return SemVer{}, fmt.Errorf("invalid level %q, use major, minor or patch", level)
+	}
+	return r, nil
+}
+
+// increment the last numeric prerelease identifier, or add one if there is none
+func (v SemVer) incPrerelease() SemVer {
+	r := SemVer{major: v.major, minor: v.minor, patch: v.patch}
+	r.pre = append([]string{}, v.pre...)
+	last := r.pre[len(r.pre)-1]
+	if isNumeric(last) {
+		n, _ := strconv.Atoi(last)
+		r.pre[len(r.pre)-1] = strconv.Itoa(n + 1)
+	} else {
+		r.pre = append(r.pre, "1")
+	}
+	return r
+}
+
+// compute the next version for a bump kind
+// level only applies to rc, beta and alpha and picks which number starts the new prerelease
+func (v SemVer) bump(kind string, level string) (SemVer, error) {
+
+	var r SemVer
+
+	switch kind {
+
+	// a prerelease of x.y.z is released as x.y.z, so only bump past it when needed
+	case "major":
+		r = SemVer{major: v.major + 1}
+		if len(v.pre) > 0 && v.minor == 0 && v.patch == 0 {
+			r.major = v.major
+		}
+	case "minor":
+		r = SemVer{major: v.major, minor: v.minor + 1}
+		if len(v.pre) > 0 && v.patch == 0 {
+			r.minor = v.minor
+		}
+	case "patch", "build":
+		r = SemVer{major: v.major, minor: v.minor, patch: v.patch + 1}
+		if len(v.pre) > 0 {
+			r.patch = v.patch
+		}
+
+	case "release":
+		if len(v.pre) == 0 {
+			return SemVer{}, fmt.Errorf("%s is not a prerelease", v)
+		}
+		r = SemVer{major: v.major, minor: v.minor, patch: v.patch}
+
+	case "pre":
+		if len(v.pre) == 0 {
+			return SemVer{}, fmt.Errorf("%s is not a prerelease, use rc, beta or alpha to start one", v)
+		}
+		r = v.incPrerelease()
+
+	case "rc", "beta", "alpha":
+		switch {
+		case level != "" || len(v.pre) == 0:
+			var err error
+			r, err = v.incLevel(level)
+			if err != nil {
+				return SemVer{}, err
+			}
+			r.pre = []string{kind, "1"}
+		case v.pre[0] == kind:
+			r = v.incPrerelease()
+		default:
+			r = SemVer{major: v.major, minor: v.minor, patch: v.patch, pre: []string{kind, "1"}}
+		}
+
+	default:
+		return SemVer{}, fmt.Errorf("invalid bump kind %q", kind)
+	}
+
+	if compareSemVer(r, v) <= 0 {
+		return SemVer{}, fmt.Errorf("%s is not greater than %s", r, v)
+	}
+	return r, nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestParseSemVer(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"v1.2.3", "1.2.3", true},
+		{"1.2.3-rc.1", "1.2.3-rc.1", true},
+		{"1.0.0-alpha-a.b-c-somethinglong+build.1-aef.1-its-okay", "1.0.0-alpha-a.b-c-somethinglong+build.1-aef.1-its-okay", true},
+		{"1.0.0+0.build.1-rc.10000aaa-kk-0.1", "1.0.0+0.build.1-rc.10000aaa-kk-0.1", true},
+		{"2.0.0+001", "2.0.0+001", true},
+		{"1.2", "", false},
+		{"1.2.3.4", "", false},
+		{"01.2.3", "", false},
+		{"1.2.3-01", "", false},
+		{"1.2.3-", "", false},
+		{"1.2.3-rc..1", "", false},
+		{"1.2.3+", "", false},
+		{"1.2.3-rc_1", "", false},
+		{"a.b.c", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := parseSemVer(tc.input)
+			if !tc.valid {
+				if err == nil {
+					t.Errorf("expected an error, got %q", v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v.String() != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, v.String())
+			}
+		})
+	}
+}
+
+func TestCompareSemVer(t *testing.T) {
+
+	// the precedence example from the semver spec, in increasing order
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := parseSemVer(ordered[i])
+		b, _ := parseSemVer(ordered[i+1])
+		if compareSemVer(a, b) != -1 {
+			t.Errorf("expected %s < %s", a, b)
+		}
+		if compareSemVer(b, a) != 1 {
+			t.Errorf("expected %s > %s", b, a)
+		}
+	}
+
+	a, _ := parseSemVer("1.0.0+build.1")
+	b, _ := parseSemVer("1.0.0+build.2")
+	if compareSemVer(a, b) != 0 {
+		t.Errorf("expected build metadata to be ignored when comparing %s and %s", a, b)
+	}
+}
+
+func TestSemVerBump(t *testing.T) {
+	testCases := []struct {
+		version  string
+		kind     string
+		level    string
+		expected string
+	}{
+		{"1.2.3", "patch", "", "1.2.4"},
+		{"1.2.3", "build", "", "1.2.4"},
+		{"1.2.3", "minor", "", "1.3.0"},
+		{"1.2.3", "major", "", "2.0.0"},
+		{"1.2.3+sha.1", "patch", "", "1.2.4"},
+		{"1.2.3-rc.1", "patch", "", "1.2.3"},
+		{"1.3.0-rc.1", "minor", "", "1.3.0"},
+		{"1.2.3-rc.1", "minor", "", "1.3.0"},
+		{"2.0.0-rc.1", "major", "", "2.0.0"},
+		{"2.1.0-rc.1", "major", "", "3.0.0"},
+		{"1.2.3-rc.1", "pre", "", "1.2.3-rc.2"},
+		{"1.2.3-beta", "pre", "", "1.2.3-beta.1"},
+		{"1.2.3", "rc", "", "1.2.4-rc.1"},
+		{"1.2.3", "rc", "minor", "1.3.0-rc.1"},
+		{"1.2.3", "alpha", "major", "2.0.0-alpha.1"},
+		{"1.2.3-rc.1", "rc", "", "1.2.3-rc.2"},
+		{"1.2.3-alpha.3", "beta", "", "1.2.3-beta.1"},
+		{"1.2.3-beta.2", "rc", "", "1.2.3-rc.1"},
+		{"1.2.3-rc.2", "release", "", "1.2.3"},
+		{"1.2.3", "pre", "", ""},
+		{"1.2.3", "release", "", ""},
+		{"1.2.3-rc.1", "alpha", "", ""},
+		{"1.2.3", "rc", "huge", ""},
+		{"1.2.3", "invalid", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version+"-"+tc.kind+"-"+tc.level, func(t *testing.T) {
+			v, err := parseSemVer(tc.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next, err := v.bump(tc.kind, tc.level)
+			if tc.expected == "" {
+				if err == nil {
+					t.Errorf("expected an error, got %q", next)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if next.String() != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, next.String())
+			}
+		})
+	}
+}
+
+func TestVersionBumpSemVer(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	testCases := []struct {
+		name     string
+		version  string
+		what     string
+		opts     BumpOptions
+		expected string
+	}{
+		{"rc", "1.2.3", "rc", BumpOptions{}, "1.2.4-rc.1"},
+		{"rc-minor", "1.2.3", "rc", BumpOptions{arg: "minor"}, "1.3.0-rc.1"},
+		{"pre", "1.2.3-rc.1", "pre", BumpOptions{}, "1.2.3-rc.2"},
+		{"release", "1.2.3-rc.2", "release", BumpOptions{}, "1.2.3"},
+		{"set", "1.2.3", "set", BumpOptions{arg: "2.0.0"}, "2.0.0"},
+		{"set-prerelease", "1.2.3", "set", BumpOptions{arg: "v2.0.0-beta.1+exp"}, "2.0.0-beta.1+exp"},
+		{"metadata", "1.2.3", "patch", BumpOptions{metadata: "sha.5114f85"}, "1.2.4+sha.5114f85"},
+		{"set-lower", "1.2.3", "set", BumpOptions{arg: "1.2.2"}, ""},
+		{"set-equal", "1.2.3", "set", BumpOptions{arg: "1.2.3+other"}, ""},
+		{"set-invalid", "1.2.3", "set", BumpOptions{arg: "2.0"}, ""},
+		{"set-missing", "1.2.3", "set", BumpOptions{}, ""},
+		{"invalid-current", "1.2", "patch", BumpOptions{}, ""},
+		{"invalid-metadata", "1.2.3", "patch", BumpOptions{metadata: "a..b"}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			originalDir, _ := os.Getwd()
+			os.Chdir(tmpDir)
+			defer os.Chdir(originalDir)
+
+			os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+			os.WriteFile("main.go", []byte("package main\n\nconst version = \""+tc.version+"\"\n"), 0644)
+
+			err := versionBump(tc.what, tc.opts)
+			version, _ := getVersion("main.go")
+
+			if tc.expected == "" {
+				if err == nil {
+					t.Errorf("expected an error, got version %q", version)
+				}
+				if version != tc.version {
+					t.Errorf("expected the version to stay %q, got %q", tc.version, version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if version != tc.expected {
+				t.Errorf("expected version %q, got %q", tc.expected, version)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/fatih/color"
+)
+
+// built-in project templates shipped with gopher
+//
+//go:embed all:templates
+var builtinTemplates embed.FS
+
+const defaultTemplate = "default"
+
+// name of the optional metadata file at the root of a template, it is never copied into the project
+const templateMetaFile = "template.json"
+
+// data made available to the project templates
+type TemplateData struct {
+	Name     string
+	Package  string
+	Username string
+	URI      string
+	Origin   string
+}
+
+// metadata describing a template
+type TemplateMeta struct {
+	Description  string `json:"description"`
+	NoGoreleaser bool   `json:"no_goreleaser"`
+}
+
+// turn a project name into a valid go package name
+func getPackageName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	pkg := b.String()
+	if pkg == "" || (pkg[0] >= '0' && pkg[0] <= '9') {
+		pkg = "pkg" + pkg
+	}
+	return pkg
+}
+
+// get the gopher configuration directory (~/.config/gopher)
+func getConfigDir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "gopher"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "gopher"), nil
+}
+
+// find a template by name, user templates take precedence over the built-in ones
+func findTemplate(name string) (fs.FS, error) {
+
+	if name == "" {
+		name = defaultTemplate
+	}
+
+	dir, err := getConfigDir()
+	if err == nil {
+		user := filepath.Join(dir, "templates", name)
+		if st, err := os.Stat(user); err == nil && st.IsDir() {
+			color.Blue("🆗 Using user template " + user)
+			return os.DirFS(user), nil
+		}
+	}
+
+	builtin, err := fs.Sub(builtinTemplates, "templates/"+name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fs.Stat(builtin, "."); err != nil {
+		fmt.Print("💥 ")
+		color.Red("Template " + name + " not found.")
+		return nil, fmt.Errorf("template %s not found", name)
+	}
+
+	color.Blue("🆗 Using built-in template " + name)
+	return builtin, nil
+}
+
+// read the template metadata file, templates without one get empty metadata
+func loadTemplateMeta(tfs fs.FS) (TemplateMeta, error) {
+
+	var meta TemplateMeta
+
+	content, err := fs.ReadFile(tfs, templateMetaFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return meta, nil
+	}
+	if err != nil {
+		return meta, err
+	}
+
+	err = json.Unmarshal(content, &meta)
+	if err != nil {
+		return meta, fmt.Errorf("invalid %s: %w", templateMetaFile, err)
+	}
+	return meta, nil
+}
+
+// list the names and descriptions of all the available templates
+func listTemplates() (map[string]TemplateMeta, error) {
+
+	templates := map[string]TemplateMeta{}
+
+	entries, err := fs.ReadDir(builtinTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		sub, _ := fs.Sub(builtinTemplates, "templates/"+e.Name())
+		meta, err := loadTemplateMeta(sub)
+		if err != nil {
+			return nil, err
+		}
+		templates[e.Name()] = meta
+	}
+
+	// user templates override the built-in ones with the same name
+	dir, err := getConfigDir()
+	if err != nil {
+		return templates, nil
+	}
+	entries, err = os.ReadDir(filepath.Join(dir, "templates"))
+	if err != nil {
+		return templates, nil
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		meta, err := loadTemplateMeta(os.DirFS(filepath.Join(dir, "templates", e.Name())))
+		if err != nil {
+			return nil, err
+		}
+		if meta.Description == "" {
+			meta.Description = "user template"
+		}
+		templates[e.Name()] = meta
+	}
+
+	return templates, nil
+}
+
+// print all the available templates with their descriptions
+func printTemplates() error {
+
+	templates, err := listTemplates()
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error listing templates")
+		color.Red(err.Error())
+		return err
+	}
+
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println()
+	color.White("📝 Available templates:")
+	for _, name := range names {
+		color.White(fmt.Sprintf("  %-16s %s", name, templates[name].Description))
+	}
+	fmt.Println()
+	color.White("💬 Use gopher init <string> --template <name> to pick one.")
+	return nil
+}
+
+// render a template string with the given data
+func renderString(name string, text string, data interface{}) (string, error) {
+
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	err = t.Execute(&out, data)
+	if err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// render every file in the template into the current directory
+// files ending in .tmpl are executed with text/template, everything else is copied as is
+func renderTemplate(tfs fs.FS, data TemplateData) error {
+
+	return fs.WalkDir(tfs, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == "." || path == templateMetaFile {
+			return nil
+		}
+
+		// file and directory names may use the template data too
+		target, err := renderString(path, path, data)
+		if err != nil {
+			fmt.Print("💥 ")
+			color.Red("Error rendering file name " + path)
+			return err
+		}
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		content, err := fs.ReadFile(tfs, path)
+		if err != nil {
+			return err
+		}
+
+		if strings.HasSuffix(target, ".tmpl") {
+			target = strings.TrimSuffix(target, ".tmpl")
+
+			rendered, err := renderString(path, string(content), data)
+			if err != nil {
+				fmt.Print("💥 ")
+				color.Red("Error rendering template " + path)
+				return err
+			}
+			content = []byte(rendered)
+		}
+
+		color.Cyan("Creating " + target + " file...")
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			fmt.Print("💥 ")
+			color.Red("Error creating " + target)
+			return err
+		}
+		color.Blue("🆗 " + target + " file created.")
+		return nil
+	})
+}
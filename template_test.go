@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fatih/color"
+)
+
+func TestRenderTemplate(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	origStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	os.Stderr = w
+	defer func() {
+		os.Stdout = origStdout
+		os.Stderr = origStderr
+	}()
+
+	data := TemplateData{
+		Name:     "myproject",
+		Username: "testuser",
+		URI:      "github.com/testuser/myproject",
+		Origin:   "git@github.com:testuser/myproject.git",
+	}
+
+	t.Run("default-template", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		tmpl, err := findTemplate(defaultTemplate)
+		if err != nil {
+			t.Fatalf("findTemplate() failed: %v", err)
+		}
+		if err := renderTemplate(tmpl, data); err != nil {
+			t.Fatalf("renderTemplate() failed: %v", err)
+		}
+
+		for _, f := range []string{"main.go", "main_test.go", "README.md", ".gitignore"} {
+			if _, err := os.Stat(f); os.IsNotExist(err) {
+				t.Errorf("%s was not created", f)
+			}
+		}
+
+		readme, _ := os.ReadFile("README.md")
+		if string(readme) != "# myproject\n" {
+			t.Errorf("expected README to contain the project name, got %q", string(readme))
+		}
+
+		main, _ := os.ReadFile("main.go")
+		if !strings.Contains(string(main), `const version = "0.1.0"`) {
+			t.Errorf("expected main.go to contain the version constant, got %q", string(main))
+		}
+	})
+
+	t.Run("custom-fs", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		tfs := fstest.MapFS{
+			"cmd/app.go.tmpl": {Data: []byte("// {{ .URI }} by {{ .Username }}\n")},
+			"LICENSE":         {Data: []byte("{{ .Name }} stays as is\n")},
+		}
+		if err := renderTemplate(tfs, data); err != nil {
+			t.Fatalf("renderTemplate() failed: %v", err)
+		}
+
+		app, err := os.ReadFile(filepath.Join("cmd", "app.go"))
+		if err != nil {
+			t.Fatalf("cmd/app.go was not created: %v", err)
+		}
+		if string(app) != "// github.com/testuser/myproject by testuser\n" {
+			t.Errorf("unexpected rendered content %q", string(app))
+		}
+
+		license, _ := os.ReadFile("LICENSE")
+		if string(license) != "{{ .Name }} stays as is\n" {
+			t.Errorf("expected non template file to be copied verbatim, got %q", string(license))
+		}
+	})
+
+	t.Run("templated-file-names", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		tfs := fstest.MapFS{
+			"{{.Name}}.go.tmpl": {Data: []byte("package {{ .Package }}\n")},
+			"template.json":     {Data: []byte(`{"description": "meta"}`)},
+		}
+		if err := renderTemplate(tfs, data); err != nil {
+			t.Fatalf("renderTemplate() failed: %v", err)
+		}
+
+		content, err := os.ReadFile("myproject.go")
+		if err != nil {
+			t.Fatalf("myproject.go was not created: %v", err)
+		}
+		if string(content) != "package \n" {
+			t.Errorf("unexpected rendered content %q", string(content))
+		}
+		if _, err := os.Stat("template.json"); !os.IsNotExist(err) {
+			t.Error("template.json should not be copied into the project")
+		}
+	})
+
+	t.Run("builtin-templates-parse", func(t *testing.T) {
+		templates, err := listTemplates()
+		if err != nil {
+			t.Fatalf("listTemplates() failed: %v", err)
+		}
+
+		data := data
+		data.Package = getPackageName(data.Name)
+
+		for name := range templates {
+			t.Run(name, func(t *testing.T) {
+				tmpDir := t.TempDir()
+				originalDir, _ := os.Getwd()
+				os.Chdir(tmpDir)
+				defer os.Chdir(originalDir)
+
+				tmpl, err := findTemplate(name)
+				if err != nil {
+					t.Fatalf("findTemplate() failed: %v", err)
+				}
+				if err := renderTemplate(tmpl, data); err != nil {
+					t.Fatalf("renderTemplate() failed: %v", err)
+				}
+
+				files, _ := filepath.Glob("*.go")
+				if len(files) == 0 {
+					t.Fatal("template did not produce any go files")
+				}
+				for _, f := range files {
+					if _, err := parser.ParseFile(token.NewFileSet(), f, nil, 0); err != nil {
+						t.Errorf("%s does not parse: %v", f, err)
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("bad-template", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		tfs := fstest.MapFS{
+			"main.go.tmpl": {Data: []byte("{{ .Name ")},
+		}
+		if err := renderTemplate(tfs, data); err == nil {
+			t.Error("expected an error for a malformed template, got nil")
+		}
+	})
+}
+
+func TestFindTemplate(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	t.Run("user-template", func(t *testing.T) {
+		cfg := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", cfg)
+
+		dir := filepath.Join(cfg, "gopher", "templates", "team")
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "main.go.tmpl"), []byte("package main\n"), 0644)
+
+		tmpl, err := findTemplate("team")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := tmpl.Open("main.go.tmpl"); err != nil {
+			t.Errorf("expected the user template to contain main.go.tmpl: %v", err)
+		}
+	})
+
+	t.Run("user-overrides-builtin", func(t *testing.T) {
+		cfg := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", cfg)
+
+		dir := filepath.Join(cfg, "gopher", "templates", defaultTemplate)
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "only.txt"), []byte("x"), 0644)
+
+		tmpl, err := findTemplate(defaultTemplate)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := tmpl.Open("only.txt"); err != nil {
+			t.Errorf("expected the user template to take precedence: %v", err)
+		}
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		_, err := findTemplate("does-not-exist")
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+}
+
+func TestListTemplates(t *testing.T) {
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	templates, err := listTemplates()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"default", "cli", "subcommand-cli", "library", "tui"} {
+		meta, ok := templates[name]
+		if !ok {
+			t.Errorf("expected built-in template %q", name)
+			continue
+		}
+		if meta.Description == "" {
+			t.Errorf("expected template %q to have a description", name)
+		}
+	}
+
+	if !templates["library"].NoGoreleaser {
+		t.Error("expected the library template to opt out of goreleaser")
+	}
+	if templates["cli"].NoGoreleaser {
+		t.Error("expected the cli template to use goreleaser")
+	}
+}
+
+func TestGetPackageName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"myproject", "myproject"},
+		{"my-project", "myproject"},
+		{"My.Project", "myproject"},
+		{"go_lib", "go_lib"},
+		{"9lives", "pkg9lives"},
+		{"---", "pkg"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := getPackageName(tc.name); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
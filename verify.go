@@ -0,0 +1,253 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// the outcome of verifying one file in dist
+type VerifyResult struct {
+	file    string
+	message string
+	ok      bool
+}
+
+// check if a file name looks like a release archive
+func isArchive(file string) bool {
+	return strings.HasSuffix(file, ".zip") || strings.HasSuffix(file, ".tar.gz") || strings.HasSuffix(file, ".tgz")
+}
+
+// read a checksums file in the sha256sum format goreleaser writes
+// returns a map of file names to hashes
+func readChecksums(file string) (map[string]string, error) {
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sums := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid line in %s: %q", file, scanner.Text())
+		}
+		// sha256sum marks binary mode with a * in front of the name
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums, scanner.Err()
+}
+
+// the binary an archive should hold, based on the os in its name
+func archiveBinaryName(name string, version string, archive string) (string, bool) {
+
+	prefix := name + "_" + version + "_"
+	if !strings.HasPrefix(archive, prefix) {
+		return "", false
+	}
+	goos, _, found := strings.Cut(strings.TrimPrefix(archive, prefix), "_")
+	if !found {
+		return "", false
+	}
+	if strings.EqualFold(goos, "windows") {
+		return name + ".exe", true
+	}
+	return name, true
+}
+
+// list the files in a zip or tar.gz archive
+func listArchive(file string) ([]string, error) {
+
+	var names []string
+
+	if strings.HasSuffix(file, ".zip") {
+		zr, err := zip.OpenReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		return names, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gr)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, h.Name)
+	}
+	return names, nil
+}
+
+// check that an archive holds the binary, at the top or in a wrapping folder
+func archiveHasBinary(file string, binary string) (bool, error) {
+	names, err := listArchive(file)
+	if err != nil {
+		return false, err
+	}
+	for _, n := range names {
+		if path.Base(n) == binary {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// compare the files in dist with the checksums file
+// every archive must be listed and match, and every listed file must exist
+func verifyDist(name string, version string) ([]VerifyResult, error) {
+
+	checksums := filepath.Join(distDir, checksumsName(name, version))
+	sums, err := readChecksums(checksums)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts, err := getDistArtifacts()
+	if err != nil {
+		return nil, err
+	}
+	present := map[string]bool{}
+	for _, a := range artifacts {
+		present[a.name] = true
+	}
+
+	var results []VerifyResult
+
+	// everything in the checksums file
+	var listed []string
+	for file := range sums {
+		listed = append(listed, file)
+	}
+	sort.Strings(listed)
+
+	for _, file := range listed {
+		if !present[file] {
+			results = append(results, VerifyResult{file: file, message: "missing from " + distDir})
+			continue
+		}
+
+		sum, err := fileSHA256(filepath.Join(distDir, file))
+		if err != nil {
+			results = append(results, VerifyResult{file: file, message: err.Error()})
+			continue
+		}
+		if sum != sums[file] {
+			results = append(results, VerifyResult{file: file, message: "checksum mismatch, got " + sum})
+			continue
+		}
+
+		message := "checksum ok"
+		if isArchive(file) {
+			if binary, ok := archiveBinaryName(name, version, file); ok {
+				found, err := archiveHasBinary(filepath.Join(distDir, file), binary)
+				if err != nil {
+					results = append(results, VerifyResult{file: file, message: "unreadable archive: " + err.Error()})
+					continue
+				}
+				if !found {
+					results = append(results, VerifyResult{file: file, message: "does not contain " + binary})
+					continue
+				}
+				message += ", contains " + binary
+			}
+		}
+		results = append(results, VerifyResult{file: file, message: message, ok: true})
+	}
+
+	// archives nobody vouched for
+	for _, a := range artifacts {
+		if _, ok := sums[a.name]; !ok && isArchive(a.name) {
+			results = append(results, VerifyResult{file: a.name, message: "not in " + filepath.Base(checksums)})
+		}
+	}
+
+	return results, nil
+}
+
+// verify the archives in dist against the checksums file
+func verify() error {
+
+	name, em := getModuleName()
+	if em != nil {
+		return em
+	}
+
+	mainfile, en := getMainFileName()
+	if en != nil {
+		return en
+	}
+
+	loc, ev := getVersionLocation(mainfile + ".go")
+	if ev != nil {
+		return ev
+	}
+	version := loc.value
+
+	checksums := filepath.Join(distDir, checksumsName(name, version))
+	color.Cyan("Verifying " + distDir + " against " + checksums + "...")
+
+	results, err := verifyDist(name, version)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error reading the release artifacts")
+		color.Red(err.Error())
+		color.White("💬  Make sure you have built the project using gopher release or gopher build --release")
+		return err
+	}
+
+	fmt.Println()
+	color.White("🔍 Artifacts:")
+	failed := 0
+	for _, r := range results {
+		if r.ok {
+			color.Green(fmt.Sprintf("  ✔  %-45s %s", r.file, r.message))
+		} else {
+			failed++
+			color.Red(fmt.Sprintf("  ✘  %-45s %s", r.file, r.message))
+		}
+	}
+	fmt.Println()
+
+	if failed > 0 {
+		fmt.Print("💥 ")
+		color.Red(fmt.Sprintf("%d of %d artifacts failed verification.", failed, len(results)))
+		return fmt.Errorf("%d artifacts failed verification", failed)
+	}
+
+	color.Green(fmt.Sprintf("✔  All %d artifacts verified.", len(results)))
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestArchiveBinaryName(t *testing.T) {
+	testCases := []struct {
+		archive  string
+		expected string
+		ok       bool
+	}{
+		{"tool_1.0.0_Windows_x86_64.zip", "tool.exe", true},
+		{"tool_1.0.0_Linux_arm64.tar.gz", "tool", true},
+		{"tool_1.0.0_Darwin_x86_64.tar.gz", "tool", true},
+		{"tool_0.9.0_Linux_x86_64.tar.gz", "", false},
+		{"other_1.0.0_Linux_x86_64.tar.gz", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.archive, func(t *testing.T) {
+			binary, ok := archiveBinaryName("tool", "1.0.0", tc.archive)
+			if ok != tc.ok || binary != tc.expected {
+				t.Errorf("expected (%q, %v), got (%q, %v)", tc.expected, tc.ok, binary, ok)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+	os.WriteFile("main.go", []byte("package main\n\nconst version = \"1.0.0\"\n"), 0644)
+
+	linux := filepath.Join(distDir, "tool_1.0.0_Linux_x86_64.tar.gz")
+	windows := filepath.Join(distDir, "tool_1.0.0_Windows_x86_64.zip")
+	checksums := filepath.Join(distDir, "tool_1.0.0_checksums.txt")
+
+	// lay out a dist folder the way a release build leaves it
+	setup := func(t *testing.T) {
+		os.RemoveAll(distDir)
+		os.MkdirAll(filepath.Join(distDir, "bin"), 0755)
+		os.WriteFile(filepath.Join(distDir, "bin", "tool"), []byte("linux binary"), 0755)
+		os.WriteFile(filepath.Join(distDir, "bin", "tool.exe"), []byte("windows binary"), 0755)
+		if err := writeTarGz(linux, []string{filepath.Join(distDir, "bin", "tool")}); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeZip(windows, []string{filepath.Join(distDir, "bin", "tool.exe")}); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeChecksums(checksums, []string{linux, windows}); err != nil {
+			t.Fatal(err)
+		}
+		buff.Reset()
+	}
+
+	testCases := []struct {
+		name    string
+		change  func(t *testing.T)
+		problem string
+	}{
+		{"all-good", func(t *testing.T) {}, ""},
+		{"tampered-archive", func(t *testing.T) {
+			os.WriteFile(linux, []byte("not the same"), 0644)
+		}, "checksum mismatch"},
+		{"missing-archive", func(t *testing.T) {
+			os.Remove(windows)
+		}, "missing from dist"},
+		{"extra-archive", func(t *testing.T) {
+			os.WriteFile(filepath.Join(distDir, "tool_1.0.0_Linux_arm64.tar.gz"), []byte("extra"), 0644)
+		}, "not in tool_1.0.0_checksums.txt"},
+		{"wrong-binary", func(t *testing.T) {
+			// a windows archive holding the linux binary
+			writeZip(windows, []string{filepath.Join(distDir, "bin", "tool")})
+			writeChecksums(checksums, []string{linux, windows})
+		}, "does not contain tool.exe"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setup(t)
+			tc.change(t)
+
+			err := verify()
+			if tc.problem == "" {
+				if err != nil {
+					t.Fatalf("verify() failed: %v\n%s", err, buff.String())
+				}
+				if !strings.Contains(buff.String(), "All 2 artifacts verified") {
+					t.Errorf("expected both archives to be verified, got %q", buff.String())
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected verify() to fail, got nil")
+			}
+			if !strings.Contains(buff.String(), tc.problem) {
+				t.Errorf("expected %q in the output, got %q", tc.problem, buff.String())
+			}
+		})
+	}
+
+	t.Run("no-checksums", func(t *testing.T) {
+		setup(t)
+		os.Remove(checksums)
+		if err := verify(); err == nil {
+			t.Error("expected an error without a checksums file, got nil")
+		}
+	})
+}
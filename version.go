@@ -0,0 +1,310 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// the places gopher can keep the version of a project
+const (
+	versionSourceGo   = "go"   // a const or var in the main package
+	versionSourceFile = "file" // a plain text file like VERSION
+	versionSourceGit  = "git"  // the latest git tag, injected with -ldflags at build time
+)
+
+// names of the identifiers gopher treats as the version declaration
+var versionNames = []string{"version", "Version"}
+
+// where the version string literal was found in the source
+type VersionLocation struct {
+	source string
+	value  string
+	file   string // the git tag for the git source
+	line   int
+	column int
+	offset int // byte offset of the string literal, quotes included
+	end    int // byte offset just past the closing quote
+	kind   string
+}
+
+// where the version was found, in the usual file:line:column form for files
+func (l VersionLocation) String() string {
+	if l.source == versionSourceGit {
+		if l.file == "" {
+			return "no git tag yet"
+		}
+		return "git tag " + l.file
+	}
+	return fmt.Sprintf("%s:%d:%d", l.file, l.line, l.column)
+}
+
+// get the configured version source
+func getVersionSource() (string, error) {
+	source := strings.ToLower(getSettingValue("version.source", ""))
+	switch source {
+	case versionSourceGo, versionSourceFile, versionSourceGit:
+		return source, nil
+	}
+	return "", fmt.Errorf("invalid version.source %q, use go, file or git", source)
+}
+
+// check if an identifier is one of the version names
+func isVersionName(name string) bool {
+	for _, n := range versionNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// look for a top level const or var named version in a parsed file
+// returns false when the file does not declare it, and an error when it does but without a string literal
+func findVersionInFile(fset *token.FileSet, file *ast.File) (VersionLocation, bool, error) {
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || (gen.Tok != token.CONST && gen.Tok != token.VAR) {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, ident := range vs.Names {
+				if !isVersionName(ident.Name) {
+					continue
+				}
+
+				pos := fset.Position(ident.Pos())
+				if i >= len(vs.Values) {
+					return VersionLocation{}, true, fmt.Errorf("%s: %s %s has no value, it is probably set with -ldflags, set version.source to git", pos, gen.Tok, ident.Name)
+				}
+
+				lit, ok := vs.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return VersionLocation{}, true, fmt.Errorf("%s: %s %s is not a string literal", pos, gen.Tok, ident.Name)
+				}
+
+				value, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					return VersionLocation{}, true, fmt.Errorf("%s: %w", pos, err)
+				}
+
+				start := fset.Position(lit.Pos())
+				return VersionLocation{
+					value:  value,
+					file:   start.Filename,
+					line:   start.Line,
+					column: start.Column,
+					offset: start.Offset,
+					end:    fset.Position(lit.End()).Offset,
+					kind:   gen.Tok.String(),
+				}, true, nil
+			}
+		}
+	}
+
+	return VersionLocation{}, false, nil
+}
+
+// find the version declaration starting with the given file and falling back
+// to the other non-test files of the same package in its directory
+func findVersion(filename string) (VersionLocation, error) {
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return VersionLocation{}, err
+	}
+
+	loc, found, err := findVersionInFile(fset, file)
+	if found {
+		return loc, err
+	}
+
+	dir := filepath.Dir(filename)
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return VersionLocation{}, err
+	}
+	sort.Strings(matches)
+
+	for _, m := range matches {
+		if m == filepath.Clean(filename) || strings.HasSuffix(m, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, m, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return VersionLocation{}, err
+		}
+		if f.Name.Name != file.Name.Name {
+			continue
+		}
+
+		loc, found, err := findVersionInFile(fset, f)
+		if found {
+			return loc, err
+		}
+	}
+
+	return VersionLocation{}, fmt.Errorf("no version constant found in package %s in %s, add a line like: const version = \"0.1.0\"", file.Name.Name, dir)
+}
+
+// read the version from a plain text file, a leading v is kept out of the value
+func findVersionFile(filename string) (VersionLocation, error) {
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return VersionLocation{}, err
+	}
+
+	text := string(content)
+	value := strings.TrimPrefix(strings.TrimSpace(text), "v")
+	if value == "" {
+		return VersionLocation{}, fmt.Errorf("%s is empty, put a version like 0.1.0 in it", filename)
+	}
+	if strings.ContainsAny(value, " \t\r\n") {
+		return VersionLocation{}, fmt.Errorf("%s should only contain the version", filename)
+	}
+
+	offset := strings.Index(text, value)
+	return VersionLocation{
+		source: versionSourceFile,
+		value:  value,
+		file:   filename,
+		line:   strings.Count(text[:offset], "\n") + 1,
+		column: offset - strings.LastIndex(text[:offset], "\n"),
+		offset: offset,
+		end:    offset + len(value),
+		kind:   "file",
+	}, nil
+}
+
+// read the version from the latest git tag, a project without tags starts at 0.0.0
+func findVersionGit() (VersionLocation, error) {
+
+	tag := getGitTag()
+	if tag == "unknown" {
+		return VersionLocation{source: versionSourceGit, value: "0.0.0", kind: "tag"}, nil
+	}
+	return VersionLocation{source: versionSourceGit, value: strings.TrimPrefix(tag, "v"), file: tag, kind: "tag"}, nil
+}
+
+// find the version in the configured source and print where it was found
+// the filename is the main file, used when the version is kept in the Go source
+func getVersionLocation(filename string) (VersionLocation, error) {
+
+	source, err := getVersionSource()
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red(err.Error())
+		return VersionLocation{}, err
+	}
+
+	var loc VersionLocation
+	switch source {
+	case versionSourceFile:
+		filename = getSettingValue("version.file", "")
+		loc, err = findVersionFile(filename)
+	case versionSourceGit:
+		loc, err = findVersionGit()
+	default:
+		if _, err := os.Stat(filename); err != nil {
+			fmt.Print("💥 ")
+			color.Red("Error opening file " + filename)
+			color.Red(err.Error())
+			return VersionLocation{}, err
+		}
+		loc, err = findVersion(filename)
+		loc.source = versionSourceGo
+	}
+
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error finding the version in " + filename)
+		color.Red(err.Error())
+		return VersionLocation{}, err
+	}
+
+	return loc, nil
+}
+
+// replace the version literal at its byte offset, leaving the rest of the file untouched
+// the file is restored if it no longer parses afterwards
+func writeVersion(loc VersionLocation, newVersion string) error {
+
+	if loc.source == versionSourceGit {
+		return fmt.Errorf("the version is read from git tags, tag the commit instead")
+	}
+
+	stat, err := os.Stat(loc.file)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(loc.file)
+	if err != nil {
+		return err
+	}
+
+	if loc.end > len(content) || loc.offset >= loc.end {
+		return fmt.Errorf("%s: the file changed since the version was read", loc)
+	}
+	// a plain version file has no quotes and no syntax to check
+	if loc.source == versionSourceFile {
+		if string(content[loc.offset:loc.end]) != loc.value {
+			return fmt.Errorf("%s: the file changed since the version was read", loc)
+		}
+		updated := string(content[:loc.offset]) + newVersion + string(content[loc.end:])
+		return os.WriteFile(loc.file, []byte(updated), stat.Mode().Perm())
+	}
+
+	old, err := strconv.Unquote(string(content[loc.offset:loc.end]))
+	if err != nil || old != loc.value {
+		return fmt.Errorf("%s: the file changed since the version was read", loc)
+	}
+
+	// keep raw string literals raw
+	literal := strconv.Quote(newVersion)
+	if content[loc.offset] == '`' && !strings.Contains(newVersion, "`") {
+		literal = "`" + newVersion + "`"
+	}
+
+	var updated []byte
+	updated = append(updated, content[:loc.offset]...)
+	updated = append(updated, literal...)
+	updated = append(updated, content[loc.end:]...)
+
+	_, err = parser.ParseFile(token.NewFileSet(), loc.file, updated, parser.SkipObjectResolution)
+	if err != nil {
+		return fmt.Errorf("%s: the rewritten file does not parse: %w", loc, err)
+	}
+
+	err = os.WriteFile(loc.file, updated, stat.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	// make sure what ended up on disk still parses and holds the new version
+	check, err := findVersion(loc.file)
+	if err != nil || check.value != newVersion {
+		os.WriteFile(loc.file, content, stat.Mode().Perm())
+		if err == nil {
+			err = fmt.Errorf("expected version %s, found %s", newVersion, check.value)
+		}
+		return fmt.Errorf("%s: the version was not updated, the file was restored: %w", loc, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,462 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestFindVersion(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		main    string
+		other   string
+		value   string
+		file    string
+		line    int
+		column  int
+		errText string
+	}{
+		{
+			name:   "simple",
+			main:   "package main\n\nconst version = \"1.2.3\"\n",
+			value:  "1.2.3",
+			file:   "main.go",
+			line:   3,
+			column: 17,
+		},
+		{
+			name:   "grouped-const",
+			main:   "package main\n\nconst (\n\tname    = \"tool\"\n\tversion = \"2.0.0\"\n)\n",
+			value:  "2.0.0",
+			file:   "main.go",
+			line:   5,
+			column: 12,
+		},
+		{
+			name:   "typed-const",
+			main:   "package main\n\nconst version string = \"1.0.1\"\n",
+			value:  "1.0.1",
+			file:   "main.go",
+			line:   3,
+			column: 24,
+		},
+		{
+			name:  "var-for-ldflags",
+			main:  "package main\n\nvar version = \"dev\"\n",
+			value: "dev",
+			file:  "main.go",
+			line:  3,
+		},
+		{
+			name:  "raw-string",
+			main:  "package main\n\nconst Version = `0.9.0`\n",
+			value: "0.9.0",
+			file:  "main.go",
+			line:  3,
+		},
+		{
+			name:  "comment-mentions-it",
+			main:  "package main\n\n// const version = \"0.0.0\" is set below\nconst version = \"3.1.4\"\n",
+			value: "3.1.4",
+			file:  "main.go",
+			line:  4,
+		},
+		{
+			name:  "declared-in-other-file",
+			main:  "package main\n\nfunc main() {}\n",
+			other: "package main\n\nconst version = \"1.1.0\"\n",
+			value: "1.1.0",
+			file:  "version.go",
+			line:  3,
+		},
+		{
+			name:    "missing",
+			main:    "package main\n\nfunc main() {}\n",
+			errText: "no version constant found",
+		},
+		{
+			name:    "no-value",
+			main:    "package main\n\nvar version string\n",
+			errText: "set with -ldflags",
+		},
+		{
+			name:    "not-a-literal",
+			main:    "package main\n\nconst version = prefix + \"1\"\n",
+			errText: "not a string literal",
+		},
+		{
+			name:    "syntax-error",
+			main:    "package main\n\nconst version = \n",
+			errText: "main.go:",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			mainGo := filepath.Join(tmpDir, "main.go")
+			os.WriteFile(mainGo, []byte(tc.main), 0644)
+			if tc.other != "" {
+				os.WriteFile(filepath.Join(tmpDir, "version.go"), []byte(tc.other), 0644)
+			}
+			// test files and other packages must be ignored
+			os.WriteFile(filepath.Join(tmpDir, "main_test.go"), []byte("package main\n\nconst Version = \"9.9.9\"\n"), 0644)
+
+			loc, err := findVersion(mainGo)
+
+			if tc.errText != "" {
+				if err == nil {
+					t.Fatalf("expected an error containing %q, got nil", tc.errText)
+				}
+				if !strings.Contains(err.Error(), tc.errText) {
+					t.Errorf("expected an error containing %q, got %q", tc.errText, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if loc.value != tc.value {
+				t.Errorf("expected value %q, got %q", tc.value, loc.value)
+			}
+			if filepath.Base(loc.file) != tc.file {
+				t.Errorf("expected file %q, got %q", tc.file, loc.file)
+			}
+			if loc.line != tc.line {
+				t.Errorf("expected line %d, got %d", tc.line, loc.line)
+			}
+			if tc.column != 0 && loc.column != tc.column {
+				t.Errorf("expected column %d, got %d", tc.column, loc.column)
+			}
+
+			// the offsets must point at the quoted literal
+			content, _ := os.ReadFile(loc.file)
+			literal := string(content[loc.offset:loc.end])
+			if strings.Trim(literal, "\"`") != tc.value {
+				t.Errorf("expected offsets to cover the literal, got %q", literal)
+			}
+		})
+	}
+}
+
+func TestGetVersionLocation(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	os.WriteFile("main.go", []byte("package main\n\nconst (\n\tversion = \"1.0.0\"\n)\n"), 0644)
+
+	loc, err := getVersionLocation("main.go")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loc.String() != "main.go:4:12" {
+		t.Errorf("expected position %q, got %q", "main.go:4:12", loc.String())
+	}
+
+	os.WriteFile("main.go", []byte("package main\n"), 0644)
+	if _, err := getVersion("main.go"); err == nil {
+		t.Error("expected an error when there is no version, got nil")
+	}
+}
+
+func TestWriteVersion(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			"odd-spacing",
+			"package main\n\nconst version   =\t\"1.2.3\" // keep me\n",
+			"package main\n\nconst version   =\t\"1.2.4\" // keep me\n",
+		},
+		{
+			"grouped-const",
+			"package main\n\nconst (\n\tname    = \"tool\"\n\tversion = \"1.2.3\"\n)\n",
+			"package main\n\nconst (\n\tname    = \"tool\"\n\tversion = \"1.2.4\"\n)\n",
+		},
+		{
+			"typed-const",
+			"package main\n\nconst version string = \"1.2.3\"\n",
+			"package main\n\nconst version string = \"1.2.4\"\n",
+		},
+		{
+			"raw-string",
+			"package main\n\nvar Version = `1.2.3`\n",
+			"package main\n\nvar Version = `1.2.4`\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mainGo := filepath.Join(t.TempDir(), "main.go")
+			os.WriteFile(mainGo, []byte(tc.content), 0644)
+
+			loc, err := findVersion(mainGo)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := writeVersion(loc, "1.2.4"); err != nil {
+				t.Fatalf("writeVersion() failed: %v", err)
+			}
+
+			content, _ := os.ReadFile(mainGo)
+			if string(content) != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, string(content))
+			}
+		})
+	}
+
+	t.Run("file-changed", func(t *testing.T) {
+		mainGo := filepath.Join(t.TempDir(), "main.go")
+		os.WriteFile(mainGo, []byte("package main\n\nconst version = \"1.2.3\"\n"), 0644)
+
+		loc, _ := findVersion(mainGo)
+		os.WriteFile(mainGo, []byte("package main\n\n// a new comment\nconst version = \"1.2.3\"\n"), 0644)
+
+		if err := writeVersion(loc, "1.2.4"); err == nil {
+			t.Error("expected an error when the file changed, got nil")
+		}
+	})
+}
+
+func TestVersionBumpOtherFile(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+	os.WriteFile("main.go", []byte("package main\n\nfunc main() {}\n"), 0644)
+	os.WriteFile("version.go", []byte("package main\n\nconst (\n\tversion string = \"0.4.9\"\n)\n"), 0644)
+
+	if err := versionBump("minor", BumpOptions{}); err != nil {
+		t.Fatalf("versionBump(, BumpOptions{}) failed: %v", err)
+	}
+
+	version, err := getVersion("main.go")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != "0.5.0" {
+		t.Errorf("expected version %q, got %q", "0.5.0", version)
+	}
+}
+
+func TestFindVersionFile(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		value   string
+		line    int
+		column  int
+	}{
+		{"plain", "1.2.3", "1.2.3", 1, 1},
+		{"trailing-newline", "1.2.3\n", "1.2.3", 1, 1},
+		{"leading-v", "v1.2.3\n", "1.2.3", 1, 2},
+		{"blank-lines", "\n\n  2.0.0-rc.1\n", "2.0.0-rc.1", 3, 3},
+		{"empty", "\n", "", 0, 0},
+		{"extra-text", "1.2.3\nsomething else\n", "", 0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "VERSION")
+			os.WriteFile(file, []byte(tc.content), 0644)
+
+			loc, err := findVersionFile(file)
+			if tc.value == "" {
+				if err == nil {
+					t.Errorf("expected an error, got %q", loc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if loc.value != tc.value || loc.line != tc.line || loc.column != tc.column {
+				t.Errorf("expected %q at %d:%d, got %q at %d:%d", tc.value, tc.line, tc.column, loc.value, loc.line, loc.column)
+			}
+
+			// rewriting keeps everything around the version
+			if err := writeVersion(loc, "9.9.9"); err != nil {
+				t.Fatalf("writeVersion() failed: %v", err)
+			}
+			content, _ := os.ReadFile(file)
+			expected := strings.Replace(tc.content, tc.value, "9.9.9", 1)
+			if string(content) != expected {
+				t.Errorf("expected %q, got %q", expected, string(content))
+			}
+		})
+	}
+}
+
+func TestVersionSources(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	origStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	os.Stderr = w
+	defer func() {
+		os.Stdout = origStdout
+		os.Stderr = origStderr
+	}()
+
+	t.Run("file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+		os.WriteFile("main.go", []byte("package main\n\nvar version string\n"), 0644)
+		os.WriteFile("VERSION", []byte("1.4.0\n"), 0644)
+		os.WriteFile(projectConfigFile, []byte("[version]\nsource = \"file\"\n"), 0644)
+
+		if version, err := getVersion("main.go"); err != nil || version != "1.4.0" {
+			t.Fatalf("expected version 1.4.0, got %q (%v)", version, err)
+		}
+
+		if err := versionBump("minor", BumpOptions{}); err != nil {
+			t.Fatalf("versionBump() failed: %v", err)
+		}
+		content, _ := os.ReadFile("VERSION")
+		if string(content) != "1.5.0\n" {
+			t.Errorf("expected VERSION to contain %q, got %q", "1.5.0\n", string(content))
+		}
+
+		loc, err := getVersionLocation("main.go")
+		if err != nil {
+			t.Fatalf("getVersionLocation() failed: %v", err)
+		}
+		if loc.value != "1.5.0" || loc.String() != "VERSION:1:1" {
+			t.Errorf("expected version 1.5.0 at VERSION:1:1, got %q at %q", loc.value, loc.String())
+		}
+	})
+
+	t.Run("custom-file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		os.WriteFile("main.go", []byte("package main\n"), 0644)
+		os.WriteFile("version.txt", []byte("0.3.1"), 0644)
+		os.WriteFile(projectConfigFile, []byte("[version]\nsource = \"file\"\nfile = \"version.txt\"\n"), 0644)
+
+		if version, err := getVersion("main.go"); err != nil || version != "0.3.1" {
+			t.Errorf("expected version 0.3.1, got %q (%v)", version, err)
+		}
+	})
+
+	t.Run("invalid-source", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		os.WriteFile("main.go", []byte("package main\n\nconst version = \"1.0.0\"\n"), 0644)
+		os.WriteFile(projectConfigFile, []byte("[version]\nsource = \"svn\"\n"), 0644)
+
+		if _, err := getVersion("main.go"); err == nil {
+			t.Error("expected an error for an invalid version source, got nil")
+		}
+	})
+
+	t.Run("git", func(t *testing.T) {
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git is not installed")
+		}
+
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(originalDir)
+
+		git := setupTestRepo(t)
+		// the go line keeps the tests pre-flight check from rewriting go.mod
+		os.WriteFile("go.mod", []byte("module github.com/user/tool\n\ngo 1.21\n"), 0644)
+		os.WriteFile("main.go", []byte("package main\n\nvar version = \"dev\"\n"), 0644)
+		os.WriteFile(projectConfigFile, []byte("[version]\nsource = \"git\"\n"), 0644)
+		git("init", "-q", "-b", "main")
+		git("add", "-A")
+		git("commit", "-q", "-m", "initial")
+
+		// only goreleaser is mocked, git is the real one
+		tmpBinDir := t.TempDir()
+		createMockExecutable(t, tmpBinDir, "goreleaser")
+		t.Setenv("PATH", tmpBinDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		// no tags yet, the first bump starts from 0.0.0
+		if version, err := getVersion("main.go"); err != nil || version != "0.0.0" {
+			t.Fatalf("expected version 0.0.0, got %q (%v)", version, err)
+		}
+		if err := release(ReleaseOptions{}); err == nil {
+			t.Error("expected release to fail without a git tag, got nil")
+		}
+		if !strings.Contains(buff.String(), "v0.0.0 does not exist") {
+			t.Errorf("expected release to complain about the missing tag, got %q", buff.String())
+		}
+
+		if err := versionBump("minor", BumpOptions{}); err != nil {
+			t.Fatalf("versionBump() failed: %v", err)
+		}
+		if version, _ := getVersion("main.go"); version != "0.1.0" {
+			t.Errorf("expected the new tag to be the version, got %q", version)
+		}
+
+		// the existing tag is released as is
+		buff.Reset()
+		if err := release(ReleaseOptions{}); err != nil {
+			t.Fatalf("release(ReleaseOptions{}) failed: %v", err)
+		}
+		if !strings.Contains(buff.String(), "Releasing the existing git tag v0.1.0") {
+			t.Errorf("expected release to use the existing tag, got %q", buff.String())
+		}
+
+		// the main file is never touched
+		content, _ := os.ReadFile("main.go")
+		if !strings.Contains(string(content), "\"dev\"") {
+			t.Errorf("expected main.go to be left alone, got %q", string(content))
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// the winget manifest schema the files are written against
+const wingetManifestVersion = "1.6.0"
+
+// the locale of the default locale manifest
+const wingetLocale = "en-US"
+
+// a winget package, written out as the version, installer and default locale manifests
+// see https://learn.microsoft.com/en-us/windows/package-manager/package/manifest
+type WingetManifest struct {
+	id          string
+	version     string
+	publisher   string
+	name        string
+	homepage    string
+	license     string
+	description string
+	binary      string
+	installers  []WingetInstaller
+}
+
+// the zip download of one architecture
+type WingetInstaller struct {
+	arch   string
+	url    string
+	sha256 string
+}
+
+// one file of the manifest set
+type WingetFile struct {
+	name    string
+	content string
+}
+
+// the winget architectures and the targets their archives are built for
+var wingetArchitectures = []struct {
+	winget string
+	goarch string
+}{
+	{"x64", "amd64"},
+	{"x86", "386"},
+	{"arm64", "arm64"},
+}
+
+// winget identifies packages as Publisher.Package
+// a subgroup owner such as group/sub publishes as sub, a slash is not allowed in the identifier
+func wingetIdentifier(owner string, name string) string {
+	return path.Base(owner) + "." + name
+}
+
+// the folder the manifests go in, laid out the way the winget-pkgs repository has them
+// e.g. manifests/u/user/tool/1.0.0
+func wingetManifestDir(id string, version string) string {
+	parts := []string{distDir, "winget", "manifests", strings.ToLower(id[:1])}
+	parts = append(parts, strings.Split(id, ".")...)
+	return filepath.Join(append(parts, version)...)
+}
+
+// quote a yaml value when it could be read as something other than a plain string
+func yamlValue(s string) string {
+
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return strconv.Quote(s)
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") || strings.ContainsAny(s, "\"\\\n\t") {
+		return strconv.Quote(s)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// pick the windows zip archives of this version from the checksums
+func findWingetInstallers(info ReleaseInfo, sums map[string]string) []WingetInstaller {
+
+	var installers []WingetInstaller
+	for _, a := range wingetArchitectures {
+		archive := archiveName(info.name, info.version, Target{goos: "windows", goarch: a.goarch}) + ".zip"
+		hash, ok := sums[archive]
+		if !ok {
+			continue
+		}
+		installers = append(installers, WingetInstaller{arch: a.winget, url: info.downloadURL(archive), sha256: strings.ToUpper(hash)})
+	}
+	return installers
+}
+
+// start a manifest file with its schema and the fields every manifest shares
+func wingetHeader(b *strings.Builder, m WingetManifest, schema string) {
+	fmt.Fprintf(b, "# yaml-language-server: $schema=https://aka.ms/winget-manifest.%s.%s.schema.json\n\n", schema, wingetManifestVersion)
+	fmt.Fprintf(b, "PackageIdentifier: %s\n", yamlValue(m.id))
+	fmt.Fprintf(b, "PackageVersion: %s\n", yamlValue(m.version))
+}
+
+// end a manifest file with its type
+func wingetFooter(b *strings.Builder, manifestType string) {
+	fmt.Fprintf(b, "ManifestType: %s\n", manifestType)
+	fmt.Fprintf(b, "ManifestVersion: %s\n", wingetManifestVersion)
+}
+
+// render the version, installer and default locale manifests
+func renderWingetManifests(m WingetManifest) []WingetFile {
+
+	var version strings.Builder
+	wingetHeader(&version, m, "version")
+	fmt.Fprintf(&version, "DefaultLocale: %s\n", wingetLocale)
+	wingetFooter(&version, "version")
+
+	// the zip holds a portable exe that winget links onto the path
+	var installer strings.Builder
+	wingetHeader(&installer, m, "installer")
+	fmt.Fprintf(&installer, "InstallerType: zip\n")
+	fmt.Fprintf(&installer, "NestedInstallerType: portable\n")
+	fmt.Fprintf(&installer, "NestedInstallerFiles:\n")
+	fmt.Fprintf(&installer, "- RelativeFilePath: %s\n", yamlValue(m.binary+".exe"))
+	fmt.Fprintf(&installer, "  PortableCommandAlias: %s\n", yamlValue(m.binary))
+	fmt.Fprintf(&installer, "Installers:\n")
+	for _, i := range m.installers {
+		fmt.Fprintf(&installer, "- Architecture: %s\n", i.arch)
+		fmt.Fprintf(&installer, "  InstallerUrl: %s\n", yamlValue(i.url))
+		fmt.Fprintf(&installer, "  InstallerSha256: %s\n", i.sha256)
+	}
+	wingetFooter(&installer, "installer")
+
+	var locale strings.Builder
+	wingetHeader(&locale, m, "defaultLocale")
+	fmt.Fprintf(&locale, "PackageLocale: %s\n", wingetLocale)
+	fmt.Fprintf(&locale, "Publisher: %s\n", yamlValue(m.publisher))
+	fmt.Fprintf(&locale, "PackageName: %s\n", yamlValue(m.name))
+	fmt.Fprintf(&locale, "PackageUrl: %s\n", yamlValue(m.homepage))
+	fmt.Fprintf(&locale, "License: %s\n", yamlValue(m.license))
+	fmt.Fprintf(&locale, "ShortDescription: %s\n", yamlValue(m.description))
+	fmt.Fprintf(&locale, "Moniker: %s\n", yamlValue(m.binary))
+	wingetFooter(&locale, "defaultLocale")
+
+	return []WingetFile{
+		{m.id + ".yaml", version.String()},
+		{m.id + ".installer.yaml", installer.String()},
+		{m.id + ".locale." + wingetLocale + ".yaml", locale.String()},
+	}
+}
+
+// generate the winget manifests for the windows archives in dist
+func generateWingetManifests() error {
+
+	color.Cyan("Generating winget manifests...")
+
+	info, err := getReleaseInfo()
+	if err != nil {
+		return err
+	}
+
+	checksum_file := filepath.Join(distDir, checksumsName(info.name, info.version))
+	color.Cyan("Reading the checksums from " + checksum_file + "...")
+	sums, err := readChecksums(checksum_file)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Could not read the checksum file: " + checksum_file)
+		color.White("💬  Make sure you have built the project using gopher release command.")
+		return err
+	}
+
+	color.Cyan("Looking for the windows archives...")
+	installers := findWingetInstallers(info, sums)
+	if len(installers) == 0 {
+		fmt.Print("💥 ")
+		color.Red("There are no Windows zip archives for version " + info.version + " in " + checksum_file)
+		return fmt.Errorf("no windows archives in %s", checksum_file)
+	}
+	for _, i := range installers {
+		color.Blue("🆗 " + i.arch + " installer: " + i.url)
+	}
+
+	manifest := WingetManifest{
+		id:          wingetIdentifier(info.owner, info.name),
+		version:     info.version,
+		publisher:   info.owner,
+		name:        info.name,
+		homepage:    info.homepage,
+		license:     info.license,
+		description: getProjectDescription(),
+		binary:      info.name,
+		installers:  installers,
+	}
+
+	if manifest.license == "" {
+		color.Yellow("⚠  No LICENSE file found, the manifest will say Freeware.")
+		manifest.license = "Freeware"
+	}
+
+	if manifest.description == "" {
+		color.Cyan("Adding generic description, you can edit it later...")
+		manifest.description = "A new winget package"
+	} else {
+		color.Blue("🆗 Description: " + manifest.description)
+	}
+
+	dir := wingetManifestDir(manifest.id, manifest.version)
+	color.Cyan("Creating the manifests in " + dir + "...")
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		fmt.Print("💥 ")
+		color.Red("Error creating " + dir)
+		color.Red(err.Error())
+		return err
+	}
+
+	for _, f := range renderWingetManifests(manifest) {
+		file := filepath.Join(dir, f.name)
+		err = os.WriteFile(file, []byte(f.content), 0644)
+		if err != nil {
+			fmt.Print("💥 ")
+			color.Red("Error creating " + file)
+			color.Red(err.Error())
+			return err
+		}
+		color.Blue("🆗 " + file)
+	}
+
+	color.Green("✔  winget manifests for " + manifest.id + " " + manifest.version + " created successfully.")
+	return nil
+}
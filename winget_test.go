@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestYamlValue(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"tool", "tool"},
+		{"1.0.0", "1.0.0"},
+		{"2.0", `"2.0"`},
+		{"https://github.com/user/tool", "https://github.com/user/tool"},
+		{"A tool: it does things", `"A tool: it does things"`},
+		{"- list", `"- list"`},
+		{"yes", `"yes"`},
+		{"say \"hi\"", `"say \"hi\""`},
+		{"", `""`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			if got := yamlValue(tc.value); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestGenerateWingetManifests(t *testing.T) {
+
+	oldOut := color.Output
+	defer func() { color.Output = oldOut }()
+	var buff bytes.Buffer
+	color.Output = &buff
+
+	origStdout := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	os.Mkdir(distDir, 0755)
+	os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+	os.WriteFile("main.go", []byte("package main\nconst version = \"2.0.0\""), 0644)
+	os.WriteFile("LICENSE", []byte("SPDX-License-Identifier: MIT\n"), 0644)
+	os.WriteFile("README.md", []byte("# tool\n\nA tool that does\nthings.\n\n## Usage\n"), 0644)
+
+	t.Run("missing-checksums", func(t *testing.T) {
+		if err := generateWingetManifests(); err == nil {
+			t.Error("expected an error without a checksums file, got nil")
+		}
+	})
+
+	t.Run("no-archives", func(t *testing.T) {
+		os.WriteFile(filepath.Join(distDir, "tool_2.0.0_checksums.txt"), []byte("111  tool_2.0.0_Linux_x86_64.tar.gz\n"), 0644)
+		if err := generateWingetManifests(); err == nil {
+			t.Error("expected an error without windows archives, got nil")
+		}
+	})
+
+	t.Run("manifests", func(t *testing.T) {
+		checksums := "aaa  tool_2.0.0_Windows_x86_64.zip\n" +
+			"bbb  tool_2.0.0_Windows_arm64.zip\n" +
+			"ccc  tool_2.0.0_Linux_x86_64.tar.gz\n"
+		os.WriteFile(filepath.Join(distDir, "tool_2.0.0_checksums.txt"), []byte(checksums), 0644)
+
+		if err := generateWingetManifests(); err != nil {
+			t.Fatalf("generateWingetManifests() failed: %v", err)
+		}
+
+		dir := filepath.Join(distDir, "winget", "manifests", "u", "user", "tool", "2.0.0")
+		expected := map[string]string{
+			"user.tool.yaml": `# yaml-language-server: $schema=https://aka.ms/winget-manifest.version.1.6.0.schema.json
+
+PackageIdentifier: user.tool
+PackageVersion: 2.0.0
+DefaultLocale: en-US
+ManifestType: version
+ManifestVersion: 1.6.0
+`,
+			"user.tool.installer.yaml": `# yaml-language-server: $schema=https://aka.ms/winget-manifest.installer.1.6.0.schema.json
+
+PackageIdentifier: user.tool
+PackageVersion: 2.0.0
+InstallerType: zip
+NestedInstallerType: portable
+NestedInstallerFiles:
+- RelativeFilePath: tool.exe
+  PortableCommandAlias: tool
+Installers:
+- Architecture: x64
+  InstallerUrl: https://github.com/user/tool/releases/download/v2.0.0/tool_2.0.0_Windows_x86_64.zip
+  InstallerSha256: AAA
+- Architecture: arm64
+  InstallerUrl: https://github.com/user/tool/releases/download/v2.0.0/tool_2.0.0_Windows_arm64.zip
+  InstallerSha256: BBB
+ManifestType: installer
+ManifestVersion: 1.6.0
+`,
+			"user.tool.locale.en-US.yaml": `# yaml-language-server: $schema=https://aka.ms/winget-manifest.defaultLocale.1.6.0.schema.json
+
+PackageIdentifier: user.tool
+PackageVersion: 2.0.0
+PackageLocale: en-US
+Publisher: user
+PackageName: tool
+PackageUrl: https://github.com/user/tool
+License: MIT
+ShortDescription: A tool that does things.
+Moniker: tool
+ManifestType: defaultLocale
+ManifestVersion: 1.6.0
+`,
+		}
+
+		for name, want := range expected {
+			content, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Errorf("expected %s to be written: %v", name, err)
+				continue
+			}
+			if string(content) != want {
+				t.Errorf("expected %s:\n%s\ngot:\n%s", name, want, string(content))
+			}
+		}
+	})
+
+	t.Run("subgroup", func(t *testing.T) {
+		os.WriteFile("go.mod", []byte("module gitlab.com/group/sub/tool"), 0644)
+		defer os.WriteFile("go.mod", []byte("module github.com/user/tool"), 0644)
+
+		if err := generateWingetManifests(); err != nil {
+			t.Fatalf("generateWingetManifests() failed: %v", err)
+		}
+
+		file := filepath.Join(distDir, "winget", "manifests", "s", "sub", "tool", "2.0.0", "sub.tool.yaml")
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", file, err)
+		}
+		if !strings.Contains(string(content), "PackageIdentifier: sub.tool\n") {
+			t.Errorf("expected the identifier to use the last owner segment, got %q", string(content))
+		}
+	})
+}